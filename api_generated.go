@@ -2,6 +2,8 @@
 
 package nano
 
+import "context"
+
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_announces.go vvvvvvvvvvvvvvvvvvvvv */
 
 // PostAnnounceInGuild 创建频道全局公告，公告类型分为 消息类型的频道公告 和 推荐子频道类型的频道公告
@@ -13,6 +15,11 @@ func (ctx *Ctx) PostAnnounceInGuild(id string, content *Announces) error {
 	return ctx.caller.PostAnnounceInGuild(id, content)
 }
 
+// PostAnnounceInGuildWithContext 同 PostAnnounceInGuild, c 结束时取消请求
+func (ctx *Ctx) PostAnnounceInGuildWithContext(c context.Context, id string, content *Announces) error {
+	return ctx.caller.WithContext(c).PostAnnounceInGuild(id, content)
+}
+
 // DeleteAnnounceInGuild 删除频道 guild_id 下指定 message_id 的全局公告
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/announces/delete_guild_announces.html
@@ -22,6 +29,11 @@ func (ctx *Ctx) DeleteAnnounceInGuild(guildid, messageid string) error {
 	return ctx.caller.DeleteAnnounceInGuild(guildid, messageid)
 }
 
+// DeleteAnnounceInGuildWithContext 同 DeleteAnnounceInGuild, c 结束时取消请求
+func (ctx *Ctx) DeleteAnnounceInGuildWithContext(c context.Context, guildid, messageid string) error {
+	return ctx.caller.WithContext(c).DeleteAnnounceInGuild(guildid, messageid)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_announces.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_audio.go vvvvvvvvvvvvvvvvvvvvv */
@@ -33,6 +45,11 @@ func (ctx *Ctx) ControlAudioInChannel(id string, control *AudioControl) error {
 	return ctx.caller.ControlAudioInChannel(id, control)
 }
 
+// ControlAudioInChannelWithContext 同 ControlAudioInChannel, c 结束时取消请求
+func (ctx *Ctx) ControlAudioInChannelWithContext(c context.Context, id string, control *AudioControl) error {
+	return ctx.caller.WithContext(c).ControlAudioInChannel(id, control)
+}
+
 // OpenMic 机器人在 channel_id 对应的语音子频道上麦
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/audio/put_mic.html
@@ -40,6 +57,11 @@ func (ctx *Ctx) OpenMicInChannel(id string) error {
 	return ctx.caller.OpenMicInChannel(id)
 }
 
+// OpenMicInChannelWithContext 同 OpenMicInChannel, c 结束时取消请求
+func (ctx *Ctx) OpenMicInChannelWithContext(c context.Context, id string) error {
+	return ctx.caller.WithContext(c).OpenMicInChannel(id)
+}
+
 // CloseMicInChannel 机器人在 channel_id 对应的语音子频道下麦
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/audio/delete_mic.html
@@ -47,6 +69,11 @@ func (ctx *Ctx) CloseMicInChannel(id string) error {
 	return ctx.caller.CloseMicInChannel(id)
 }
 
+// CloseMicInChannelWithContext 同 CloseMicInChannel, c 结束时取消请求
+func (ctx *Ctx) CloseMicInChannelWithContext(c context.Context, id string) error {
+	return ctx.caller.WithContext(c).CloseMicInChannel(id)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_audio.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_channel.go vvvvvvvvvvvvvvvvvvvvv */
@@ -58,6 +85,11 @@ func (ctx *Ctx) GetChannelsOfGuild(id string) (channels []Channel, err error) {
 	return ctx.caller.GetChannelsOfGuild(id)
 }
 
+// GetChannelsOfGuildWithContext 同 GetChannelsOfGuild, c 结束时取消请求
+func (ctx *Ctx) GetChannelsOfGuildWithContext(c context.Context, id string) (channels []Channel, err error) {
+	return ctx.caller.WithContext(c).GetChannelsOfGuild(id)
+}
+
 // GetChannelByID 用于获取 channel_id 指定的子频道的详情
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel/get_channel.html
@@ -65,6 +97,11 @@ func (ctx *Ctx) GetChannelByID(id string) (*Channel, error) {
 	return ctx.caller.GetChannelByID(id)
 }
 
+// GetChannelByIDWithContext 同 GetChannelByID, c 结束时取消请求
+func (ctx *Ctx) GetChannelByIDWithContext(c context.Context, id string) (*Channel, error) {
+	return ctx.caller.WithContext(c).GetChannelByID(id)
+}
+
 // CreateChannelInGuild 用于在 guild_id 指定的频道下创建一个子频道
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel/post_channels.html
@@ -72,6 +109,11 @@ func (ctx *Ctx) CreateChannelInGuild(id string, config *ChannelPost) (*Channel,
 	return ctx.caller.CreateChannelInGuild(id, config)
 }
 
+// CreateChannelInGuildWithContext 同 CreateChannelInGuild, c 结束时取消请求
+func (ctx *Ctx) CreateChannelInGuildWithContext(c context.Context, id string, config *ChannelPost) (*Channel, error) {
+	return ctx.caller.WithContext(c).CreateChannelInGuild(id, config)
+}
+
 // PatchChannelOf 修改 channel_id 指定的子频道的信息
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel/patch_channel.html
@@ -79,6 +121,11 @@ func (ctx *Ctx) PatchChannelOf(id string, config *ChannelPatch) (*Channel, error
 	return ctx.caller.PatchChannelOf(id, config)
 }
 
+// PatchChannelOfWithContext 同 PatchChannelOf, c 结束时取消请求
+func (ctx *Ctx) PatchChannelOfWithContext(c context.Context, id string, config *ChannelPatch) (*Channel, error) {
+	return ctx.caller.WithContext(c).PatchChannelOf(id, config)
+}
+
 // DeleteChannelOf 删除 channel_id 指定的子频道
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel/delete_channel.html
@@ -86,6 +133,11 @@ func (ctx *Ctx) DeleteChannelOf(id string) error {
 	return ctx.caller.DeleteChannelOf(id)
 }
 
+// DeleteChannelOfWithContext 同 DeleteChannelOf, c 结束时取消请求
+func (ctx *Ctx) DeleteChannelOfWithContext(c context.Context, id string) error {
+	return ctx.caller.WithContext(c).DeleteChannelOf(id)
+}
+
 // GetOnlineNumsInChannel 查询音视频/直播子频道 channel_id 的在线成员数
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel/get_online_nums.html
@@ -93,6 +145,11 @@ func (ctx *Ctx) GetOnlineNumsInChannel(id string) (int, error) {
 	return ctx.caller.GetOnlineNumsInChannel(id)
 }
 
+// GetOnlineNumsInChannelWithContext 同 GetOnlineNumsInChannel, c 结束时取消请求
+func (ctx *Ctx) GetOnlineNumsInChannelWithContext(c context.Context, id string) (int, error) {
+	return ctx.caller.WithContext(c).GetOnlineNumsInChannel(id)
+}
+
 // GetChannelPermissionsOfUser 获取子频道 channel_id 下用户 user_id 的权限
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel_permissions/get_channel_permissions.html
@@ -100,6 +157,11 @@ func (ctx *Ctx) GetChannelPermissionsOfUser(channelid, userid string) (*ChannelP
 	return ctx.caller.GetChannelPermissionsOfUser(channelid, userid)
 }
 
+// GetChannelPermissionsOfUserWithContext 同 GetChannelPermissionsOfUser, c 结束时取消请求
+func (ctx *Ctx) GetChannelPermissionsOfUserWithContext(c context.Context, channelid, userid string) (*ChannelPermissions, error) {
+	return ctx.caller.WithContext(c).GetChannelPermissionsOfUser(channelid, userid)
+}
+
 // SetChannelPermissionsOfUser 修改子频道 channel_id 下用户 user_id 的权限
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel_permissions/put_channel_permissions.html
@@ -107,6 +169,11 @@ func (ctx *Ctx) SetChannelPermissionsOfUser(channelid, userid string, add, remov
 	return ctx.caller.SetChannelPermissionsOfUser(channelid, userid, add, remove)
 }
 
+// SetChannelPermissionsOfUserWithContext 同 SetChannelPermissionsOfUser, c 结束时取消请求
+func (ctx *Ctx) SetChannelPermissionsOfUserWithContext(c context.Context, channelid, userid string, add, remove string) error {
+	return ctx.caller.WithContext(c).SetChannelPermissionsOfUser(channelid, userid, add, remove)
+}
+
 // GetChannelPermissionsOfRole 获取子频道 channel_id 下身份组 role_id 的权限
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel_permissions/get_channel_roles_permissions.html
@@ -114,6 +181,11 @@ func (ctx *Ctx) GetChannelPermissionsOfRole(channelid, roleid string) (*ChannelP
 	return ctx.caller.GetChannelPermissionsOfRole(channelid, roleid)
 }
 
+// GetChannelPermissionsOfRoleWithContext 同 GetChannelPermissionsOfRole, c 结束时取消请求
+func (ctx *Ctx) GetChannelPermissionsOfRoleWithContext(c context.Context, channelid, roleid string) (*ChannelPermissions, error) {
+	return ctx.caller.WithContext(c).GetChannelPermissionsOfRole(channelid, roleid)
+}
+
 // SetChannelPermissionsOfRole 修改子频道 channel_id 下身份组 role_id 的权限
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/channel_permissions/put_channel_roles_permissions.html
@@ -121,6 +193,11 @@ func (ctx *Ctx) SetChannelPermissionsOfRole(channelid, roleid string, add, remov
 	return ctx.caller.SetChannelPermissionsOfRole(channelid, roleid, add, remove)
 }
 
+// SetChannelPermissionsOfRoleWithContext 同 SetChannelPermissionsOfRole, c 结束时取消请求
+func (ctx *Ctx) SetChannelPermissionsOfRoleWithContext(c context.Context, channelid, roleid string, add, remove string) error {
+	return ctx.caller.WithContext(c).SetChannelPermissionsOfRole(channelid, roleid, add, remove)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_channel.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_codegen_getopenapiof.go vvvvvvvvvvvvvvvvvvvvv */
@@ -148,6 +225,11 @@ func (ctx *Ctx) CreatePrivateChat(guildid, userid string) (*DMS, error) {
 	return ctx.caller.CreatePrivateChat(guildid, userid)
 }
 
+// CreatePrivateChatWithContext 同 CreatePrivateChat, c 结束时取消请求
+func (ctx *Ctx) CreatePrivateChatWithContext(c context.Context, guildid, userid string) (*DMS, error) {
+	return ctx.caller.WithContext(c).CreatePrivateChat(guildid, userid)
+}
+
 // PostMessageToUser 发送私信消息，前提是已经创建了私信会话
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/dms/post_dms_messages.html
@@ -157,6 +239,11 @@ func (ctx *Ctx) PostMessageToUser(id string, content *MessagePost) (*Message, er
 	return ctx.caller.PostMessageToUser(id, content)
 }
 
+// PostMessageToUserWithContext 同 PostMessageToUser, c 结束时取消请求
+func (ctx *Ctx) PostMessageToUserWithContext(c context.Context, id string, content *MessagePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostMessageToUser(id, content)
+}
+
 // DeleteMessageOfUser 撤回私信频道 guild_id 中 message_id 指定的私信消息, 只能用于撤回机器人自己发送的私信
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/dms/delete_dms.html
@@ -164,6 +251,11 @@ func (ctx *Ctx) DeleteMessageOfUser(guildid, messageid string, hidetip bool) err
 	return ctx.caller.DeleteMessageOfUser(guildid, messageid, hidetip)
 }
 
+// DeleteMessageOfUserWithContext 同 DeleteMessageOfUser, c 结束时取消请求
+func (ctx *Ctx) DeleteMessageOfUserWithContext(c context.Context, guildid, messageid string, hidetip bool) error {
+	return ctx.caller.WithContext(c).DeleteMessageOfUser(guildid, messageid, hidetip)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_dms.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_emoji.go vvvvvvvvvvvvvvvvvvvvv */
@@ -175,6 +267,11 @@ func (ctx *Ctx) GiveMessageReaction(channelid, messageid string, emoji Emoji) er
 	return ctx.caller.GiveMessageReaction(channelid, messageid, emoji)
 }
 
+// GiveMessageReactionWithContext 同 GiveMessageReaction, c 结束时取消请求
+func (ctx *Ctx) GiveMessageReactionWithContext(c context.Context, channelid, messageid string, emoji Emoji) error {
+	return ctx.caller.WithContext(c).GiveMessageReaction(channelid, messageid, emoji)
+}
+
 // DeleteMessageReaction 删除自己对消息 message_id 的表情表态
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/reaction/delete_own_message_reaction.html
@@ -182,6 +279,11 @@ func (ctx *Ctx) DeleteMessageReaction(channelid, messageid string, emoji Emoji)
 	return ctx.caller.DeleteMessageReaction(channelid, messageid, emoji)
 }
 
+// DeleteMessageReactionWithContext 同 DeleteMessageReaction, c 结束时取消请求
+func (ctx *Ctx) DeleteMessageReactionWithContext(c context.Context, channelid, messageid string, emoji Emoji) error {
+	return ctx.caller.WithContext(c).DeleteMessageReaction(channelid, messageid, emoji)
+}
+
 // GetMessageReactionUsers 拉取对消息 message_id 指定表情表态的用户列表
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/reaction/get_reaction_users.html
@@ -189,6 +291,11 @@ func (ctx *Ctx) GetMessageReactionUsers(channelid, messageid string, emoji Emoji
 	return ctx.caller.GetMessageReactionUsers(channelid, messageid, emoji, cookie, limit)
 }
 
+// GetMessageReactionUsersWithContext 同 GetMessageReactionUsers, c 结束时取消请求
+func (ctx *Ctx) GetMessageReactionUsersWithContext(c context.Context, channelid, messageid string, emoji Emoji, cookie string, limit int) (*MessageReactionUsers, error) {
+	return ctx.caller.WithContext(c).GetMessageReactionUsers(channelid, messageid, emoji, cookie, limit)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_emoji.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_forum.go vvvvvvvvvvvvvvvvvvvvv */
@@ -200,6 +307,11 @@ func (ctx *Ctx) GetChannelThreads(id string) (threads []Thread, isfinish bool, e
 	return ctx.caller.GetChannelThreads(id)
 }
 
+// GetChannelThreadsWithContext 同 GetChannelThreads, c 结束时取消请求
+func (ctx *Ctx) GetChannelThreadsWithContext(c context.Context, id string) (threads []Thread, isfinish bool, err error) {
+	return ctx.caller.WithContext(c).GetChannelThreads(id)
+}
+
 // GetThreadInfo 获取子频道下的帖子详情
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/get_thread.html
@@ -207,6 +319,11 @@ func (ctx *Ctx) GetThreadInfo(channelid, threadid string) (*ThreadInfo, error) {
 	return ctx.caller.GetThreadInfo(channelid, threadid)
 }
 
+// GetThreadInfoWithContext 同 GetThreadInfo, c 结束时取消请求
+func (ctx *Ctx) GetThreadInfoWithContext(c context.Context, channelid, threadid string) (*ThreadInfo, error) {
+	return ctx.caller.WithContext(c).GetThreadInfo(channelid, threadid)
+}
+
 // PostThread 发表帖子
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/put_thread.html
@@ -214,6 +331,23 @@ func (ctx *Ctx) PostThreadInChannel(id string, title string, content string, for
 	return ctx.caller.PostThreadInChannel(id, title, content, format)
 }
 
+// PostThreadInChannelWithContext 同 PostThreadInChannel, c 结束时取消请求
+func (ctx *Ctx) PostThreadInChannelWithContext(c context.Context, id string, title string, content string, format uint32) (taskid string, createtime string, err error) {
+	return ctx.caller.WithContext(c).PostThreadInChannel(id, title, content, format)
+}
+
+// PostRichThreadInChannel 以 json 格式发表富文本帖子
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/put_thread.html
+func (ctx *Ctx) PostRichThreadInChannel(id string, title string, content *RichText) (taskid string, createtime string, err error) {
+	return ctx.caller.PostRichThreadInChannel(id, title, content)
+}
+
+// PostRichThreadInChannelWithContext 同 PostRichThreadInChannel, c 结束时取消请求
+func (ctx *Ctx) PostRichThreadInChannelWithContext(c context.Context, id string, title string, content *RichText) (taskid string, createtime string, err error) {
+	return ctx.caller.WithContext(c).PostRichThreadInChannel(id, title, content)
+}
+
 // DeleteThreadInChannel 删除指定子频道下的某个帖子
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/delete_thread.html
@@ -221,6 +355,11 @@ func (ctx *Ctx) DeleteThreadInChannel(channelid, threadid string) error {
 	return ctx.caller.DeleteThreadInChannel(channelid, threadid)
 }
 
+// DeleteThreadInChannelWithContext 同 DeleteThreadInChannel, c 结束时取消请求
+func (ctx *Ctx) DeleteThreadInChannelWithContext(c context.Context, channelid, threadid string) error {
+	return ctx.caller.WithContext(c).DeleteThreadInChannel(channelid, threadid)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_forum.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_guild.go vvvvvvvvvvvvvvvvvvvvv */
@@ -232,6 +371,11 @@ func (ctx *Ctx) GetGuildByID(id string) (*Guild, error) {
 	return ctx.caller.GetGuildByID(id)
 }
 
+// GetGuildByIDWithContext 同 GetGuildByID, c 结束时取消请求
+func (ctx *Ctx) GetGuildByIDWithContext(c context.Context, id string) (*Guild, error) {
+	return ctx.caller.WithContext(c).GetGuildByID(id)
+}
+
 // SetAllMuteInGuild 禁言全员 / 解除全员禁言
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/patch_guild_mute.html
@@ -239,6 +383,11 @@ func (ctx *Ctx) SetAllMuteInGuild(id string, endtimestamp string, seconds string
 	return ctx.caller.SetAllMuteInGuild(id, endtimestamp, seconds)
 }
 
+// SetAllMuteInGuildWithContext 同 SetAllMuteInGuild, c 结束时取消请求
+func (ctx *Ctx) SetAllMuteInGuildWithContext(c context.Context, id string, endtimestamp string, seconds string) error {
+	return ctx.caller.WithContext(c).SetAllMuteInGuild(id, endtimestamp, seconds)
+}
+
 // SetUserMuteInGuild 禁言 / 解除禁言频道 guild_id 下的成员 user_id
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/patch_guild_mute.html
@@ -246,6 +395,11 @@ func (ctx *Ctx) SetUserMuteInGuild(guildid, userid string, endtimestamp string,
 	return ctx.caller.SetUserMuteInGuild(guildid, userid, endtimestamp, seconds)
 }
 
+// SetUserMuteInGuildWithContext 同 SetUserMuteInGuild, c 结束时取消请求
+func (ctx *Ctx) SetUserMuteInGuildWithContext(c context.Context, guildid, userid string, endtimestamp string, seconds string) error {
+	return ctx.caller.WithContext(c).SetUserMuteInGuild(guildid, userid, endtimestamp, seconds)
+}
+
 // SetUsersMuteInGuild 批量禁言 / 解除禁言频道 guild_id 下的成员 user_id
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/patch_guild_mute.html
@@ -253,8 +407,29 @@ func (ctx *Ctx) SetUsersMuteInGuild(guildid string, endtimestamp string, seconds
 	return ctx.caller.SetUsersMuteInGuild(guildid, endtimestamp, seconds, userids...)
 }
 
+// SetUsersMuteInGuildWithContext 同 SetUsersMuteInGuild, c 结束时取消请求
+func (ctx *Ctx) SetUsersMuteInGuildWithContext(c context.Context, guildid string, endtimestamp string, seconds string, userids ...string) ([]string, error) {
+	return ctx.caller.WithContext(c).SetUsersMuteInGuild(guildid, endtimestamp, seconds, userids...)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_guild.go ^^^^^^^^^^^^^^^^^^^^ */
 
+/* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_interaction.go vvvvvvvvvvvvvvvvvvvvv */
+
+// PutInteractionResult 回应互动事件 interaction_id, 否则用户客户端将一直显示加载中
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/trans/msg-btn.html#%E5%9B%9E%E8%B0%83%E6%93%8D%E4%BD%9C
+func (ctx *Ctx) PutInteractionResult(id string, code InteractionResultCode) error {
+	return ctx.caller.PutInteractionResult(id, code)
+}
+
+// PutInteractionResultWithContext 同 PutInteractionResult, c 结束时取消请求
+func (ctx *Ctx) PutInteractionResultWithContext(c context.Context, id string, code InteractionResultCode) error {
+	return ctx.caller.WithContext(c).PutInteractionResult(id, code)
+}
+
+/* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_interaction.go ^^^^^^^^^^^^^^^^^^^^ */
+
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_markdown.go vvvvvvvvvvvvvvvvvvvvv */
 
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_markdown.go ^^^^^^^^^^^^^^^^^^^^ */
@@ -268,6 +443,11 @@ func (ctx *Ctx) GetGuildMembersIn(id, after string, limit uint32) (members []Mem
 	return ctx.caller.GetGuildMembersIn(id, after, limit)
 }
 
+// GetGuildMembersInWithContext 同 GetGuildMembersIn, c 结束时取消请求
+func (ctx *Ctx) GetGuildMembersInWithContext(c context.Context, id, after string, limit uint32) (members []Member, err error) {
+	return ctx.caller.WithContext(c).GetGuildMembersIn(id, after, limit)
+}
+
 // GetRoleMembersOf 获取 guild_id 频道中指定role_id身份组下所有成员的详情列表，支持分页
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/member/get_role_members.html
@@ -275,6 +455,11 @@ func (ctx *Ctx) GetRoleMembersOf(guildid, roleid, startindex string, limit uint3
 	return ctx.caller.GetRoleMembersOf(guildid, roleid, startindex, limit)
 }
 
+// GetRoleMembersOfWithContext 同 GetRoleMembersOf, c 结束时取消请求
+func (ctx *Ctx) GetRoleMembersOfWithContext(c context.Context, guildid, roleid, startindex string, limit uint32) (*RoleMembers, error) {
+	return ctx.caller.WithContext(c).GetRoleMembersOf(guildid, roleid, startindex, limit)
+}
+
 // GetGuildMemberOf 获取 guild_id 指定的频道中 user_id 对应成员的详细信息
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/member/get_member.html
@@ -282,6 +467,11 @@ func (ctx *Ctx) GetGuildMemberOf(guildid, userid string) (*Member, error) {
 	return ctx.caller.GetGuildMemberOf(guildid, userid)
 }
 
+// GetGuildMemberOfWithContext 同 GetGuildMemberOf, c 结束时取消请求
+func (ctx *Ctx) GetGuildMemberOfWithContext(c context.Context, guildid, userid string) (*Member, error) {
+	return ctx.caller.WithContext(c).GetGuildMemberOf(guildid, userid)
+}
+
 // DeleteGuildMemberOf 删除 guild_id 指定的频道下的成员 user_id
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/member/delete_member.html
@@ -291,6 +481,11 @@ func (ctx *Ctx) DeleteGuildMemberOf(guildid, userid string, addblklst bool, delh
 	return ctx.caller.DeleteGuildMemberOf(guildid, userid, addblklst, delhistmsgdays)
 }
 
+// DeleteGuildMemberOfWithContext 同 DeleteGuildMemberOf, c 结束时取消请求
+func (ctx *Ctx) DeleteGuildMemberOfWithContext(c context.Context, guildid, userid string, addblklst bool, delhistmsgdays int) error {
+	return ctx.caller.WithContext(c).DeleteGuildMemberOf(guildid, userid, addblklst, delhistmsgdays)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_member.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_message.go vvvvvvvvvvvvvvvvvvvvv */
@@ -302,6 +497,11 @@ func (ctx *Ctx) GetMessageFromChannel(messageid, channelid string) (*Message, er
 	return ctx.caller.GetMessageFromChannel(messageid, channelid)
 }
 
+// GetMessageFromChannelWithContext 同 GetMessageFromChannel, c 结束时取消请求
+func (ctx *Ctx) GetMessageFromChannelWithContext(c context.Context, messageid, channelid string) (*Message, error) {
+	return ctx.caller.WithContext(c).GetMessageFromChannel(messageid, channelid)
+}
+
 // PostMessageToChannel 向 channel_id 指定的子频道发送消息
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/message/post_messages.html
@@ -309,6 +509,11 @@ func (ctx *Ctx) PostMessageToChannel(id string, content *MessagePost) (*Message,
 	return ctx.caller.PostMessageToChannel(id, content)
 }
 
+// PostMessageToChannelWithContext 同 PostMessageToChannel, c 结束时取消请求
+func (ctx *Ctx) PostMessageToChannelWithContext(c context.Context, id string, content *MessagePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostMessageToChannel(id, content)
+}
+
 // DeleteMessageInChannel 回子频道 channel_id 下的消息 message_id
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/message/delete_message.html
@@ -316,6 +521,11 @@ func (ctx *Ctx) DeleteMessageInChannel(channelid, messageid string, hidetip bool
 	return ctx.caller.DeleteMessageInChannel(channelid, messageid, hidetip)
 }
 
+// DeleteMessageInChannelWithContext 同 DeleteMessageInChannel, c 结束时取消请求
+func (ctx *Ctx) DeleteMessageInChannelWithContext(c context.Context, channelid, messageid string, hidetip bool) error {
+	return ctx.caller.WithContext(c).DeleteMessageInChannel(channelid, messageid, hidetip)
+}
+
 // GetGuildMessageSetting 获取机器人在频道 guild_id 内的消息频率设置
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/setting/message_setting.html
@@ -323,10 +533,41 @@ func (ctx *Ctx) GetGuildMessageSetting(id string) (*MessageSetting, error) {
 	return ctx.caller.GetGuildMessageSetting(id)
 }
 
+// GetGuildMessageSettingWithContext 同 GetGuildMessageSetting, c 结束时取消请求
+func (ctx *Ctx) GetGuildMessageSettingWithContext(c context.Context, id string) (*MessageSetting, error) {
+	return ctx.caller.WithContext(c).GetGuildMessageSetting(id)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_message.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_permissions.go vvvvvvvvvvvvvvvvvvvvv */
 
+// GetAPIPermissionsOfGuild 获取机器人在频道 guild_id 内可以使用的权限列表
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/get_guild_api_permission.html
+func (ctx *Ctx) GetAPIPermissionsOfGuild(id string) ([]APIPermission, error) {
+	return ctx.caller.GetAPIPermissionsOfGuild(id)
+}
+
+// GetAPIPermissionsOfGuildWithContext 同 GetAPIPermissionsOfGuild, c 结束时取消请求
+func (ctx *Ctx) GetAPIPermissionsOfGuildWithContext(c context.Context, id string) ([]APIPermission, error) {
+	return ctx.caller.WithContext(c).GetAPIPermissionsOfGuild(id)
+}
+
+// PostAPIPermissionDemandInGuild 发送机器人在频道 guild_id 内的接口权限授权链接到子频道 channel_id
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/post_api_permission_demand.html
+//
+// desc 为机器人申请对应的 API 接口权限后可以使用功能的描述
+func (ctx *Ctx) PostAPIPermissionDemandInGuild(guildid, channelid string, api APIPermissionDemandIdentify, desc string) (*APIPermissionDemand, error) {
+	return ctx.caller.PostAPIPermissionDemandInGuild(guildid, channelid, api, desc)
+}
+
+// PostAPIPermissionDemandInGuildWithContext 同 PostAPIPermissionDemandInGuild, c 结束时取消请求
+func (ctx *Ctx) PostAPIPermissionDemandInGuildWithContext(c context.Context, guildid, channelid string, api APIPermissionDemandIdentify, desc string) (*APIPermissionDemand, error) {
+	return ctx.caller.WithContext(c).PostAPIPermissionDemandInGuild(guildid, channelid, api, desc)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_permissions.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_pins.go vvvvvvvvvvvvvvvvvvvvv */
@@ -338,6 +579,11 @@ func (ctx *Ctx) PinMessageInChannel(channelid, messageid string) (*PinsMessage,
 	return ctx.caller.PinMessageInChannel(channelid, messageid)
 }
 
+// PinMessageInChannelWithContext 同 PinMessageInChannel, c 结束时取消请求
+func (ctx *Ctx) PinMessageInChannelWithContext(c context.Context, channelid, messageid string) (*PinsMessage, error) {
+	return ctx.caller.WithContext(c).PinMessageInChannel(channelid, messageid)
+}
+
 // UnpinMessageInChannel 子频道 channel_id 下指定 message_id 的精华消息
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/pins/delete_pins_message.html
@@ -347,6 +593,11 @@ func (ctx *Ctx) UnpinMessageInChannel(channelid, messageid string) error {
 	return ctx.caller.UnpinMessageInChannel(channelid, messageid)
 }
 
+// UnpinMessageInChannelWithContext 同 UnpinMessageInChannel, c 结束时取消请求
+func (ctx *Ctx) UnpinMessageInChannelWithContext(c context.Context, channelid, messageid string) error {
+	return ctx.caller.WithContext(c).UnpinMessageInChannel(channelid, messageid)
+}
+
 // GetPinMessagesOfChannel 获取子频道 channel_id 内的精华消息
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/pins/get_pins_message.html
@@ -354,6 +605,11 @@ func (ctx *Ctx) GetPinMessagesOfChannel(id string) (*PinsMessage, error) {
 	return ctx.caller.GetPinMessagesOfChannel(id)
 }
 
+// GetPinMessagesOfChannelWithContext 同 GetPinMessagesOfChannel, c 结束时取消请求
+func (ctx *Ctx) GetPinMessagesOfChannelWithContext(c context.Context, id string) (*PinsMessage, error) {
+	return ctx.caller.WithContext(c).GetPinMessagesOfChannel(id)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_pins.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_richobj.go vvvvvvvvvvvvvvvvvvvvv */
@@ -369,6 +625,11 @@ func (ctx *Ctx) GetGuildRoleListIn(id string) (*GuildRoleList, error) {
 	return ctx.caller.GetGuildRoleListIn(id)
 }
 
+// GetGuildRoleListInWithContext 同 GetGuildRoleListIn, c 结束时取消请求
+func (ctx *Ctx) GetGuildRoleListInWithContext(c context.Context, id string) (*GuildRoleList, error) {
+	return ctx.caller.WithContext(c).GetGuildRoleListIn(id)
+}
+
 // CreateGuildRoleOf 创建频道身份组
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/post_guild_role.html
@@ -378,6 +639,11 @@ func (ctx *Ctx) CreateGuildRoleOf(id string, name string, color uint32, hoist in
 	return ctx.caller.CreateGuildRoleOf(id, name, color, hoist)
 }
 
+// CreateGuildRoleOfWithContext 同 CreateGuildRoleOf, c 结束时取消请求
+func (ctx *Ctx) CreateGuildRoleOfWithContext(c context.Context, id string, name string, color uint32, hoist int32) (*GuildRoleCreate, error) {
+	return ctx.caller.WithContext(c).CreateGuildRoleOf(id, name, color, hoist)
+}
+
 // PatchGuildRoleOf 修改频道 guild_id 下 role_id 指定的身份组
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/patch_guild_role.html
@@ -385,6 +651,11 @@ func (ctx *Ctx) PatchGuildRoleOf(guildid, roleid string, name string, color uint
 	return ctx.caller.PatchGuildRoleOf(guildid, roleid, name, color, hoist)
 }
 
+// PatchGuildRoleOfWithContext 同 PatchGuildRoleOf, c 结束时取消请求
+func (ctx *Ctx) PatchGuildRoleOfWithContext(c context.Context, guildid, roleid string, name string, color uint32, hoist int32) (*GuildRolePatch, error) {
+	return ctx.caller.WithContext(c).PatchGuildRoleOf(guildid, roleid, name, color, hoist)
+}
+
 // DeleteGuildRoleOf 删除频道 guild_id下 role_id 对应的身份组
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/delete_guild_role.html
@@ -392,6 +663,11 @@ func (ctx *Ctx) DeleteGuildRoleOf(guildid, roleid string) error {
 	return ctx.caller.DeleteGuildRoleOf(guildid, roleid)
 }
 
+// DeleteGuildRoleOfWithContext 同 DeleteGuildRoleOf, c 结束时取消请求
+func (ctx *Ctx) DeleteGuildRoleOfWithContext(c context.Context, guildid, roleid string) error {
+	return ctx.caller.WithContext(c).DeleteGuildRoleOf(guildid, roleid)
+}
+
 // AddRoleToMemberOfGuild 将频道 guild_id 下的用户 user_id 添加到身份组 role_id
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/put_guild_member_role.html
@@ -401,6 +677,11 @@ func (ctx *Ctx) AddRoleToMemberOfGuild(guildid, userid, roleid, channelid string
 	return ctx.caller.AddRoleToMemberOfGuild(guildid, userid, roleid, channelid)
 }
 
+// AddRoleToMemberOfGuildWithContext 同 AddRoleToMemberOfGuild, c 结束时取消请求
+func (ctx *Ctx) AddRoleToMemberOfGuildWithContext(c context.Context, guildid, userid, roleid, channelid string) (string, error) {
+	return ctx.caller.WithContext(c).AddRoleToMemberOfGuild(guildid, userid, roleid, channelid)
+}
+
 // RemoveRoleFromMemberOfGuild 将用户 user_id 从 频道 guild_id 的 role_id 身份组中移除
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/guild/delete_guild_member_role.html
@@ -408,6 +689,11 @@ func (ctx *Ctx) RemoveRoleFromMemberOfGuild(guildid, userid, roleid, channelid s
 	return ctx.caller.RemoveRoleFromMemberOfGuild(guildid, userid, roleid, channelid)
 }
 
+// RemoveRoleFromMemberOfGuildWithContext 同 RemoveRoleFromMemberOfGuild, c 结束时取消请求
+func (ctx *Ctx) RemoveRoleFromMemberOfGuildWithContext(c context.Context, guildid, userid, roleid, channelid string) error {
+	return ctx.caller.WithContext(c).RemoveRoleFromMemberOfGuild(guildid, userid, roleid, channelid)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_role.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_schedule.go vvvvvvvvvvvvvvvvvvvvv */
@@ -419,6 +705,11 @@ func (ctx *Ctx) GetChannelSchedules(id string, since uint64) (schedules []Schedu
 	return ctx.caller.GetChannelSchedules(id, since)
 }
 
+// GetChannelSchedulesWithContext 同 GetChannelSchedules, c 结束时取消请求
+func (ctx *Ctx) GetChannelSchedulesWithContext(c context.Context, id string, since uint64) (schedules []Schedule, err error) {
+	return ctx.caller.WithContext(c).GetChannelSchedules(id, since)
+}
+
 // GetScheduleInChannel 获取日程子频道 channel_id 下 schedule_id 指定的的日程的详情
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/schedule/get_schedule.html
@@ -426,6 +717,11 @@ func (ctx *Ctx) GetScheduleInChannel(channelid string, scheduleid string) (*Sche
 	return ctx.caller.GetScheduleInChannel(channelid, scheduleid)
 }
 
+// GetScheduleInChannelWithContext 同 GetScheduleInChannel, c 结束时取消请求
+func (ctx *Ctx) GetScheduleInChannelWithContext(c context.Context, channelid string, scheduleid string) (*Schedule, error) {
+	return ctx.caller.WithContext(c).GetScheduleInChannel(channelid, scheduleid)
+}
+
 // CreateScheduleInChannel 在 channel_id 指定的日程子频道下创建一个日程
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/schedule/post_schedule.html
@@ -435,6 +731,11 @@ func (ctx *Ctx) CreateScheduleInChannel(id string, schedule *Schedule) error {
 	return ctx.caller.CreateScheduleInChannel(id, schedule)
 }
 
+// CreateScheduleInChannelWithContext 同 CreateScheduleInChannel, c 结束时取消请求
+func (ctx *Ctx) CreateScheduleInChannelWithContext(c context.Context, id string, schedule *Schedule) error {
+	return ctx.caller.WithContext(c).CreateScheduleInChannel(id, schedule)
+}
+
 // PatchScheduleInChannel 修改日程子频道 channel_id 下 schedule_id 指定的日程的详情
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/schedule/patch_schedule.html
@@ -444,6 +745,11 @@ func (ctx *Ctx) PatchScheduleInChannel(channelid string, scheduleid string, sche
 	return ctx.caller.PatchScheduleInChannel(channelid, scheduleid, schedule)
 }
 
+// PatchScheduleInChannelWithContext 同 PatchScheduleInChannel, c 结束时取消请求
+func (ctx *Ctx) PatchScheduleInChannelWithContext(c context.Context, channelid string, scheduleid string, schedule *Schedule) error {
+	return ctx.caller.WithContext(c).PatchScheduleInChannel(channelid, scheduleid, schedule)
+}
+
 // DeleteScheduleInChannel 删除日程子频道 channel_id 下 schedule_id 指定的日程
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/schedule/delete_schedule.html
@@ -451,6 +757,11 @@ func (ctx *Ctx) DeleteScheduleInChannel(channelid string, scheduleid string) err
 	return ctx.caller.DeleteScheduleInChannel(channelid, scheduleid)
 }
 
+// DeleteScheduleInChannelWithContext 同 DeleteScheduleInChannel, c 结束时取消请求
+func (ctx *Ctx) DeleteScheduleInChannelWithContext(c context.Context, channelid string, scheduleid string) error {
+	return ctx.caller.WithContext(c).DeleteScheduleInChannel(channelid, scheduleid)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_schedule.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_user.go vvvvvvvvvvvvvvvvvvvvv */
@@ -460,6 +771,11 @@ func (ctx *Ctx) AtMe() string {
 	return ctx.caller.AtMe()
 }
 
+// AtMeWithContext 同 AtMe, c 结束时取消请求
+func (ctx *Ctx) AtMeWithContext(c context.Context) string {
+	return ctx.caller.WithContext(c).AtMe()
+}
+
 // GetMyInfo 获取当前用户（机器人）详情
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/user/me.html
@@ -467,6 +783,11 @@ func (ctx *Ctx) GetMyInfo() (*User, error) {
 	return ctx.caller.GetMyInfo()
 }
 
+// GetMyInfoWithContext 同 GetMyInfo, c 结束时取消请求
+func (ctx *Ctx) GetMyInfoWithContext(c context.Context) (*User, error) {
+	return ctx.caller.WithContext(c).GetMyInfo()
+}
+
 // GetMyGuilds 获取当前用户（机器人）频道列表，支持分页
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/user/guilds.html
@@ -474,6 +795,11 @@ func (ctx *Ctx) GetMyGuilds(before, after string, limit int) (guilds []Guild, er
 	return ctx.caller.GetMyGuilds(before, after, limit)
 }
 
+// GetMyGuildsWithContext 同 GetMyGuilds, c 结束时取消请求
+func (ctx *Ctx) GetMyGuildsWithContext(c context.Context, before, after string, limit int) (guilds []Guild, err error) {
+	return ctx.caller.WithContext(c).GetMyGuilds(before, after, limit)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_user.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_v2.go vvvvvvvvvvvvvvvvvvvvv */
@@ -489,6 +815,11 @@ func (ctx *Ctx) PostFileToQQUser(id string, content *FilePost) (*Message, error)
 	return ctx.caller.PostFileToQQUser(id, content)
 }
 
+// PostFileToQQUserWithContext 同 PostFileToQQUser, c 结束时取消请求
+func (ctx *Ctx) PostFileToQQUserWithContext(c context.Context, id string, content *FilePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostFileToQQUser(id, content)
+}
+
 // PostFileToQQGroup 发送文件到 QQ 群的 openid
 //
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html#%E5%8F%91%E9%80%81%E5%88%B0%E7%BE%A4%E8%81%8A
@@ -496,6 +827,11 @@ func (ctx *Ctx) PostFileToQQGroup(id string, content *FilePost) (*Message, error
 	return ctx.caller.PostFileToQQGroup(id, content)
 }
 
+// PostFileToQQGroupWithContext 同 PostFileToQQGroup, c 结束时取消请求
+func (ctx *Ctx) PostFileToQQGroupWithContext(c context.Context, id string, content *FilePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostFileToQQGroup(id, content)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_v2_files.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_v2_message.go vvvvvvvvvvvvvvvvvvvvv */
@@ -507,6 +843,11 @@ func (ctx *Ctx) PostMessageToQQUser(id string, content *MessagePost) (*Message,
 	return ctx.caller.PostMessageToQQUser(id, content)
 }
 
+// PostMessageToQQUserWithContext 同 PostMessageToQQUser, c 结束时取消请求
+func (ctx *Ctx) PostMessageToQQUserWithContext(c context.Context, id string, content *MessagePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostMessageToQQUser(id, content)
+}
+
 // PostMessageToQQGroup 向 openid 指定的群发送消息
 //
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/send.html#%E7%BE%A4%E8%81%8A
@@ -514,6 +855,35 @@ func (ctx *Ctx) PostMessageToQQGroup(id string, content *MessagePost) (*Message,
 	return ctx.caller.PostMessageToQQGroup(id, content)
 }
 
+// PostMessageToQQGroupWithContext 同 PostMessageToQQGroup, c 结束时取消请求
+func (ctx *Ctx) PostMessageToQQGroupWithContext(c context.Context, id string, content *MessagePost) (*Message, error) {
+	return ctx.caller.WithContext(c).PostMessageToQQGroup(id, content)
+}
+
+// DeleteMessageOfQQUser 撤回机器人发送给 openid 指定用户的消息 message_id
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/send-receive/rich-media.html#%E6%92%A4%E5%9B%9E%E6%B6%88%E6%81%AF
+func (ctx *Ctx) DeleteMessageOfQQUser(id, messageid string) error {
+	return ctx.caller.DeleteMessageOfQQUser(id, messageid)
+}
+
+// DeleteMessageOfQQUserWithContext 同 DeleteMessageOfQQUser, c 结束时取消请求
+func (ctx *Ctx) DeleteMessageOfQQUserWithContext(c context.Context, id, messageid string) error {
+	return ctx.caller.WithContext(c).DeleteMessageOfQQUser(id, messageid)
+}
+
+// DeleteMessageInQQGroup 撤回机器人在 openid 指定的群发送的消息 message_id
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/send-receive/rich-media.html#%E6%92%A4%E5%9B%9E%E6%B6%88%E6%81%AF
+func (ctx *Ctx) DeleteMessageInQQGroup(id, messageid string) error {
+	return ctx.caller.DeleteMessageInQQGroup(id, messageid)
+}
+
+// DeleteMessageInQQGroupWithContext 同 DeleteMessageInQQGroup, c 结束时取消请求
+func (ctx *Ctx) DeleteMessageInQQGroupWithContext(c context.Context, id, messageid string) error {
+	return ctx.caller.WithContext(c).DeleteMessageInQQGroup(id, messageid)
+}
+
 /* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 openapi_v2_message.go ^^^^^^^^^^^^^^^^^^^^ */
 
 /* vvvvvvvvvvvvvvvvvvvv 生成自文件 openapi_wss.go vvvvvvvvvvvvvvvvvvvvv */
@@ -0,0 +1,123 @@
+package nano
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// APIErrorKind 错误码的分类
+type APIErrorKind uint8
+
+const (
+	APIErrorUnknown      APIErrorKind = iota // APIErrorUnknown 未知分类
+	APIErrorTokenExpired                     // APIErrorTokenExpired Token 失效
+	APIErrorRateLimited                      // APIErrorRateLimited 频率限制
+	APIErrorNoPermission                     // APIErrorNoPermission 无权限
+	APIErrorAuditPending                     // APIErrorAuditPending 消息已提交审核
+)
+
+// APIErrorCodes 已知的 OpenAPI 错误码, 可自行补充
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/error/error.html
+var APIErrorCodes = map[int]APIErrorKind{
+	11244:  APIErrorTokenExpired, // token 不存在或已过期
+	11264:  APIErrorNoPermission, // 接口需要申请权限
+	11265:  APIErrorNoPermission, // 接口权限被禁用
+	20028:  APIErrorRateLimited,  // 子频道消息触发限频
+	22009:  APIErrorRateLimited,  // 消息发送超频
+	304023: APIErrorAuditPending, // 消息已提交审核
+	304024: APIErrorAuditPending, // 主动消息已提交审核
+}
+
+// APIError OpenAPI 返回的错误
+type APIError struct {
+	Status   int    // Status HTTP 状态码
+	Code     int    // Code 即 CodeMessageBase.C
+	Message  string // Message 即 CodeMessageBase.M
+	Method   string // Method 请求方法
+	Endpoint string // Endpoint 请求的 ep
+	TraceID  string // TraceID 响应头 X-Tps-trace-ID
+	Err      error  // Err 解析响应时出现的错误
+}
+
+func (e *APIError) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString(e.Method)
+	sb.WriteByte(' ')
+	sb.WriteString(e.Endpoint)
+	if e.Status >= http.StatusBadRequest {
+		sb.WriteString(", code: ")
+		sb.WriteString(strconv.Itoa(e.Status))
+		sb.WriteByte(' ')
+		sb.WriteString(http.StatusText(e.Status))
+	}
+	if e.Err != nil {
+		sb.WriteString(", json: ")
+		sb.WriteString(e.Err.Error())
+	}
+	if e.Code != 0 {
+		sb.WriteString(", err: [")
+		sb.WriteString(strconv.Itoa(e.Code))
+		sb.WriteString("] ")
+		if len([]rune(e.Message)) > 256 {
+			sb.WriteString(string([]rune(e.Message)[:256]))
+			sb.WriteString("...")
+		} else {
+			sb.WriteString(e.Message)
+		}
+	}
+	if e.TraceID != "" {
+		sb.WriteString(", trace: ")
+		sb.WriteString(e.TraceID)
+	}
+	return sb.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Kind 优先按错误码, 其次按 HTTP 状态码分类
+func (e *APIError) Kind() APIErrorKind {
+	if k, ok := APIErrorCodes[e.Code]; ok {
+		return k
+	}
+	switch e.Status {
+	case http.StatusUnauthorized:
+		return APIErrorTokenExpired
+	case http.StatusForbidden:
+		return APIErrorNoPermission
+	case http.StatusTooManyRequests:
+		return APIErrorRateLimited
+	}
+	return APIErrorUnknown
+}
+
+// isapierror err 是否为 kind 类的 *APIError
+func isapierror(err error, kind APIErrorKind) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.Kind() == kind
+}
+
+// IsTokenExpired 是否因 Token 失效而失败
+func IsTokenExpired(err error) bool {
+	return isapierror(err, APIErrorTokenExpired)
+}
+
+// IsRateLimited 是否触发频率限制
+func IsRateLimited(err error) bool {
+	return isapierror(err, APIErrorRateLimited)
+}
+
+// IsNoPermission 是否无权限
+func IsNoPermission(err error) bool {
+	return isapierror(err, APIErrorNoPermission)
+}
+
+// IsAuditPending 消息是否已提交审核, 此时消息并未发送失败
+func IsAuditPending(err error) bool {
+	return isapierror(err, APIErrorAuditPending)
+}
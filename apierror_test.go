@@ -0,0 +1,52 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Tps-trace-ID", "trace-"+r.URL.Path[1:])
+		switch r.URL.Path {
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":22009,"message":"msg limit exceed"}`))
+		case "/audit":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"code":304023,"message":"push message is waiting for audit now"}`))
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`{"id":"1","username":"nano"}`))
+		}
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	err := bot.PostOpenAPI("/limited", "", &CodeMessageBase{}, nil)
+	var apierr *APIError
+	assert.True(t, errors.As(err, &apierr))
+	assert.Equal(t, http.StatusTooManyRequests, apierr.Status)
+	assert.Equal(t, 22009, apierr.Code)
+	assert.Equal(t, http.MethodPost, apierr.Method)
+	assert.Equal(t, "/limited", apierr.Endpoint)
+	assert.Equal(t, "trace-limited", apierr.TraceID)
+	assert.True(t, IsRateLimited(err))
+	assert.False(t, IsNoPermission(err))
+
+	err = bot.PostOpenAPI("/audit", "", &CodeMessageBase{}, nil)
+	assert.True(t, IsAuditPending(err))
+
+	err = bot.DeleteOpenAPI("/forbidden", "", nil)
+	assert.True(t, IsNoPermission(err))
+
+	_, err = bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.False(t, IsRateLimited(nil))
+}
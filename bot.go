@@ -1,10 +1,10 @@
 package nano
 
 import (
-	"encoding/base64"
+	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
-	"net"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -23,6 +23,10 @@ var (
 	isrunning uintptr
 )
 
+var (
+	ErrShutdownTimeout = errors.New("shutdown timeout")
+)
+
 const (
 	// SuperUserAllQQUsers 使所有 QQ 用户成为超级用户
 	SuperUserAllQQUsers = "AllQQUsers"
@@ -30,9 +34,8 @@ const (
 
 // Bot 一个机器人实例的配置
 type Bot struct {
-	AppID      string          `yaml:"AppID"` // AppID is BotAppID（开发者ID）
-	Token      string          `yaml:"Token"` // Token is 机器人令牌 有 Secret 则使用新版 API
-	token      string          // token 是通过 secret 获得的残血 token
+	AppID      string          `yaml:"AppID"`      // AppID is BotAppID（开发者ID）
+	Token      string          `yaml:"Token"`      // Token is 机器人令牌 有 Secret 则使用新版 API
 	Secret     string          `yaml:"Secret"`     // Secret is 机器人令牌 V2 (AppSecret/ClientSecret) 沙盒目前虽然能登录但无法收发消息
 	SuperUsers []string        `yaml:"SuperUsers"` // SuperUsers 超级用户, 特殊: AllQQUsers 将使所有 QQ 用户成为超级用户
 	Timeout    time.Duration   `yaml:"Timeout"`    // Timeout is API 调用超时
@@ -42,17 +45,64 @@ type Bot struct {
 	ShardCount uint8           `yaml:"ShardCount"` // ShardCount 分片总数
 	shard      [2]byte         // shard 分片
 	Properties json.RawMessage `yaml:"Properties"` // Properties 一些环境变量, 目前没用
+	// ShutdownTimeout is Close 时等待处理中事件的最长时间, 默认 30 秒
+	ShutdownTimeout time.Duration `yaml:"ShutdownTimeout"`
+	// HeartbeatMaxMissed 连续多少次心跳未收到 ACK 即视为连接失效, 默认 3
+	HeartbeatMaxMissed uint32 `yaml:"HeartbeatMaxMissed"`
+	// TokenRefreshFraction 在 Token 有效期的多少比例处刷新, 默认 0.8
+	TokenRefreshFraction float64 `yaml:"TokenRefreshFraction"`
+	// APIBase OpenAPI 域名, 默认使用 OpenAPI, 支持 http+unix 等形式
+	APIBase string `yaml:"APIBase"`
+	// AccessTokenURL 获取接口凭证的 API, 默认 AccessTokenAPI
+	AccessTokenURL string `yaml:"AccessTokenURL"`
+	// Transport 调用 OpenAPI 使用的 http.RoundTripper, 可配置代理、TLS 等
+	Transport http.RoundTripper `yaml:"-"`
+	// Dialer 连接网关使用的 websocket.Dialer
+	Dialer *websocket.Dialer `yaml:"-"`
+	// RetryPolicy 调用 OpenAPI 遇到临时错误时的重试策略, 默认 DefaultRetryPolicy
+	RetryPolicy *RetryPolicy `yaml:"-"`
+	// Interceptors 调用 OpenAPI 时依次经过的拦截器, 第一个在最外层
+	Interceptors []Interceptor `yaml:"-"`
+	// MediaUploader 在 QQ 发送字节形式的图片、语音与视频时使用, 默认 FileDataUploader
+	MediaUploader MediaUploader `yaml:"-"`
+	// FileInfoCache 缓存 QQ 富媒体的 file_info, 默认在内存中, 可使用 DiskFileInfoCache
+	FileInfoCache FileInfoCache `yaml:"-"`
+	// OnThrottled 调用 OpenAPI 因限流等待 d 后被调用, 可用于统计
+	OnThrottled func(route, target string, d time.Duration) `yaml:"-"`
+	// ReconnectPolicy 断线重连策略, 默认 DefaultReconnectPolicy
+	ReconnectPolicy ReconnectPolicy `yaml:"-"`
+	// SessionStore 保存网关会话以便重启后 Resume, 为 nil 时不保存, 可使用 DefaultSessionStore
+	SessionStore SessionStore `yaml:"-"`
 
 	gateway   string                      // gateway 获得的网关
 	seq       uint32                      // seq 最新的 s
 	heartbeat uint32                      // heartbeat 心跳周期, 单位毫秒
-	expiresec int64                       // expiresec Token 有效时间
+	hbmissed  uint32                      // hbmissed 连续未收到 ACK 的心跳数
+	hbsent    int64                       // hbsent 最近一次发送心跳的时间, 单位纳秒
+	hbacked   int64                       // hbacked 最近一次收到 ACK 的时间, 单位纳秒
+	latency   int64                       // latency 最近一次心跳到 ACK 的往返时间, 单位纳秒
+	token     atomic.Pointer[accessToken] // token 是通过 secret 获得的残血 token
+	tkmu      sync.Mutex                  // tkmu 保证同时只有一个刷新 Token 的请求
 	handlers  map[string]eventHandlerType // handlers 方便调用的 handler
 	mu        sync.Mutex                  // 写锁
 	conn      *websocket.Conn             // conn 目前的 wss 连接
 	hbonce    sync.Once                   // hbonce 保证仅执行一次 heartbeat
 	exonce    sync.Once                   // exonce 保证仅执行一次刷新 token
 	client    *http.Client                // client 主要配置 timeout
+	whonce    sync.Once                   // whonce 保证仅派生一次回调密钥
+	pmonce    sync.Once                   // pmonce 保证仅检查一次接口权限, 分片使用 owner 的
+	whkey     ed25519.PrivateKey          // whkey 由 Secret 派生的回调签名密钥
+	ctx       context.Context             // ctx 控制心跳、刷新 Token 与重连的生命周期
+	cancel    context.CancelFunc          // cancel 取消 ctx
+	reqctxs   []context.Context           // reqctxs WithContext 视图的 ctx, 仅作用于 OpenAPI 调用
+	evmu      sync.RWMutex                // evmu 保护 closing 与 wg
+	closing   bool                        // closing 已开始关闭, 不再接受新事件
+	wg        sync.WaitGroup              // wg 处理中的事件
+	budget    *sessionBudget              // budget identify 额度
+	limiter   rateLimiter                 // limiter OpenAPI 限流
+	ficache   MemoryFileInfoCache         // ficache 默认的 FileInfoCache
+	owner     *Bot                        // owner 自动分片时持有 Token 与 HTTP 客户端的模版
+	reshard   func()                      // reshard 网关要求重新分片时调用
 
 	ready EventReady // ready 连接成功后下发的 bot 基本信息
 }
@@ -62,16 +112,38 @@ func (ctx *Ctx) GetReady() *EventReady {
 	return &ctx.caller.ready
 }
 
+// Latency 最近一次心跳到 ACK 的往返时间, 尚未收到 ACK 时为 0
+func (bot *Bot) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&bot.latency))
+}
+
+// LastHeartbeatACK 最近一次收到心跳 ACK 的时间, 尚未收到时为零值
+func (bot *Bot) LastHeartbeatACK() time.Time {
+	t := atomic.LoadInt64(&bot.hbacked)
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
+// Latency 最近一次心跳到 ACK 的往返时间, 尚未收到 ACK 时为 0
+func (ctx *Ctx) Latency() time.Duration {
+	return ctx.caller.Latency()
+}
+
 // getinitinfo 获得 gateway 和 shard
 func (bot *Bot) getinitinfo() (secret, gw string, shard [2]byte, err error) {
 	shard[1] = 1
 	if bot.client == nil {
-		bot.client = http.DefaultClient
+		bot.client = bot.newclient()
 	}
 	secret = bot.Secret
 	if bot.Secret != "" {
 		bot.Secret = ""
 	}
+	if bot.budget == nil {
+		bot.budget = &sessionBudget{}
+	}
 	if bot.ShardIndex == 0 {
 		gw, err = bot.GetGeneralWSSGatewayNoContext()
 		if err != nil {
@@ -83,6 +155,7 @@ func (bot *Bot) getinitinfo() (secret, gw string, shard [2]byte, err error) {
 		if err != nil {
 			return
 		}
+		bot.budget.update(sgw)
 		if bot.ShardCount == 0 {
 			log.Infoln(getLogHeader(), "使用网关推荐Shards数:", sgw.Shards)
 			bot.ShardCount = uint8(sgw.Shards)
@@ -152,15 +225,196 @@ func Run(preblock func(), bots ...*Bot) error {
 	return nil
 }
 
+// RunContext 运行 bots 直到 ctx 结束, 随后关闭所有 bot 并等待处理中的事件完成
+func RunContext(ctx context.Context, bots ...*Bot) error {
+	if !atomic.CompareAndSwapUintptr(&isrunning, 0, 1) {
+		log.Warnln(getLogHeader(), "已忽略重复调用的", getThisFuncName())
+	}
+	for i, b := range bots {
+		s, gw, shard, err := b.getinitinfo()
+		if err != nil {
+			_ = closeBots(bots[:i]...)
+			return err
+		}
+		b.ctx, b.cancel = context.WithCancel(ctx)
+		go b.Init(s, gw, shard).Connect().Listen()
+	}
+	<-ctx.Done()
+	return closeBots(bots...)
+}
+
+// closeBots 并发关闭 bots
+func closeBots(bots ...*Bot) error {
+	errs := make([]error, len(bots))
+	wg := sync.WaitGroup{}
+	wg.Add(len(bots))
+	for i, b := range bots {
+		go func(i int, b *Bot) {
+			defer wg.Done()
+			errs[i] = b.Close()
+		}(i, b)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close 停止心跳、刷新 Token 与重连, 发送关闭帧断开网关连接,
+// 不再接受新事件并在 ShutdownTimeout 内等待处理中的事件完成
+func (bot *Bot) Close() error {
+	bot.evmu.Lock()
+	if bot.closing {
+		bot.evmu.Unlock()
+		return nil
+	}
+	bot.closing = true
+	bot.evmu.Unlock()
+	if bot.cancel != nil {
+		bot.cancel()
+	}
+	clients.Delete(bot.clientkey())
+	atomic.StoreUint32(&bot.heartbeat, 0)
+	var err error
+	bot.mu.Lock()
+	conn := bot.conn
+	bot.mu.Unlock()
+	bot.savesession()
+	if conn != nil {
+		code := websocket.CloseNormalClosure
+		if bot.SessionStore != nil {
+			code = closeCodeResumable // 以非 1000 关闭, 使已保存的会话仍可恢复
+		}
+		err = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(code, ""), time.Now().Add(time.Second))
+		_ = conn.Close()
+	}
+	timeout := bot.ShutdownTimeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	done := make(chan struct{})
+	go func() {
+		bot.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Infoln(getLogHeader(), "AppID:", bot.AppID, "已关闭")
+	case <-time.After(timeout):
+		log.Warnln(getLogHeader(), "AppID:", bot.AppID, "关闭时等待处理中的事件超时")
+		err = errors.Join(err, ErrShutdownTimeout)
+	}
+	return err
+}
+
+// sleep 等待 d, 期间 bot 被关闭或视图的 ctx 结束则返回 false
+func (bot *Bot) sleep(d time.Duration) bool {
+	ctx, cancel := bot.requestcontext()
+	defer cancel()
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// context 未 Init 时为 context.Background()
+func (bot *Bot) context() context.Context {
+	if bot.ctx == nil {
+		return context.Background()
+	}
+	return bot.ctx
+}
+
+// requestcontext 合并 bot 与 WithContext 的 ctx, 请求结束后须调用 cancel
+func (bot *Bot) requestcontext() (context.Context, context.CancelFunc) {
+	ctx, cancels := bot.context(), []context.CancelFunc(nil)
+	for _, c := range bot.reqctxs {
+		var cancel context.CancelFunc
+		ctx, cancel = mergecontext(ctx, c)
+		if cancel != nil {
+			cancels = append(cancels, cancel)
+		}
+	}
+	return ctx, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+// mergecontext 返回 a 或 b 结束时结束的 ctx, 无需合并时 cancel 为 nil
+func mergecontext(a, b context.Context) (context.Context, context.CancelFunc) {
+	switch {
+	case b.Done() == nil:
+		return a, nil
+	case a.Done() == nil:
+		return b, nil
+	}
+	ctx, cancel := context.WithCancel(b)
+	go func() {
+		select {
+		case <-a.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// WithContext 返回 bot 的浅拷贝视图, 通过视图调用的 OpenAPI 在 ctx 结束或 bot 关闭时被取消
+//
+// 视图仅复制配置并额外携带 ctx, Token, 限流, FileInfo 缓存与 HTTP 客户端均取自 bot 的 owner (或 bot 本身),
+// 视图不可用于连接与 Close
+func (bot *Bot) WithContext(ctx context.Context) *Bot {
+	if ctx.Done() == nil {
+		return bot
+	}
+	b := bot.cloneconfig()
+	b.Secret = bot.Secret
+	b.gateway = bot.gateway
+	b.shard = bot.shard
+	b.ready = bot.ready
+	b.owner = bot.tokenholder()
+	b.client = bot.client
+	if b.client == nil {
+		b.client = b.owner.client
+	}
+	b.ctx = bot.context()
+	b.reqctxs = append(bot.reqctxs[:len(bot.reqctxs):len(bot.reqctxs)], ctx)
+	return b
+}
+
+// clientkey 在 clients 中的键
+func (bot *Bot) clientkey() string {
+	return bot.Token + "_" + strconv.Itoa(int(bot.shard[0]))
+}
+
 // Init 初始化, 只需执行一次
 func (bot *Bot) Init(secret, gateway string, shard [2]byte) *Bot {
+	if bot.ctx == nil {
+		bot.ctx, bot.cancel = context.WithCancel(context.Background())
+	}
 	bot.gateway = gateway
 	bot.shard = shard
 	if bot.Timeout == 0 {
 		bot.Timeout = time.Minute
 	}
-	bot.client = &http.Client{
-		Timeout: bot.Timeout,
+	if bot.TokenRefreshFraction <= 0 || bot.TokenRefreshFraction >= 1 {
+		bot.TokenRefreshFraction = 0.8
+	}
+	if bot.HeartbeatMaxMissed == 0 {
+		bot.HeartbeatMaxMissed = 3
+	}
+	if bot.budget == nil {
+		bot.budget = &sessionBudget{}
+	}
+	if bot.owner != nil {
+		bot.client = bot.owner.client
+	} else {
+		bot.client = bot.newclient()
 	}
 	if bot.Handler != nil {
 		h := reflect.ValueOf(bot.Handler).Elem()
@@ -181,18 +435,21 @@ func (bot *Bot) Init(secret, gateway string, shard [2]byte) *Bot {
 		}
 	}
 	bot.Secret = secret
-	if bot.IsV2() {
+	if bot.IsV2() && bot.owner == nil {
 		for {
 			err := bot.GetAppAccessTokenNoContext()
 			if err == nil {
-				log.Infoln(getLogHeader(), "获得 Token: "+bot.token+", 超时:", bot.expiresec, "秒")
+				tk := bot.token.Load()
+				log.Infoln(getLogHeader(), "获得 Token: "+tk.token+", 超时:", tk.expiresec, "秒")
 				bot.exonce.Do(func() {
 					go bot.refreshtoken()
 				})
 				break
 			}
 			log.Infoln(getLogHeader(), "获得 Token 失败:", err)
-			time.Sleep(time.Second * 3)
+			if !bot.sleep(time.Second * 3) {
+				break
+			}
 		}
 	}
 	return bot
@@ -206,7 +463,11 @@ func (bot *Bot) IsV2() bool {
 // Authorization 返回 Authorization Header value
 func (bot *Bot) Authorization() string {
 	if bot.IsV2() {
-		return "QQBot " + bot.token
+		tk := bot.accesstoken()
+		if tk == nil {
+			return "QQBot "
+		}
+		return "QQBot " + tk.token
 	}
 	return "Bot " + bot.AppID + "." + bot.Token
 }
@@ -221,44 +482,46 @@ func (bot *Bot) reveive() (payload WebsocketPayload, err error) {
 //
 // https://bot.q.qq.com/wiki/develop/api/gateway/reference.html#_1-%E8%BF%9E%E6%8E%A5%E5%88%B0-gateway
 func (bot *Bot) Connect() *Bot {
+	if bot.SessionStore != nil && bot.ready.SessionID == "" && bot.restoresession() {
+		clients.Store(bot.clientkey(), bot)
+		bot.hbonce.Do(func() {
+			go bot.doheartbeat()
+		})
+		return bot
+	}
 	network, address := resolveURI(bot.gateway)
 	log.Infoln(getLogHeader(), "开始尝试连接到网关:", address, ", AppID:", bot.AppID)
-	dialer := websocket.Dialer{
-		NetDial: func(_, addr string) (net.Conn, error) {
-			if network == "unix" {
-				host, _, err := net.SplitHostPort(addr)
-				if err != nil {
-					host = addr
-				}
-				filepath, err := base64.RawURLEncoding.DecodeString(host)
-				if err == nil {
-					addr = BytesToString(filepath)
-				}
-			}
-			return net.Dial(network, addr) // support unix socket transport
-		},
-	}
-	for {
-		conn, resp, err := dialer.Dial(address, http.Header{})
+	dialer := bot.wsdialer(network)
+	policy := bot.reconnectPolicy()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && !bot.sleep(policy.Backoff(attempt)) { // 退避后重新连接
+			return bot
+		}
+		if bot.ctx.Err() != nil {
+			return bot
+		}
+		if !bot.waitidentify() { // 连接前等待额度, 连接失败时额度按已消耗计
+			return bot
+		}
+		conn, resp, err := dialer.DialContext(bot.ctx, address, http.Header{})
 		if err != nil {
 			log.Warnf(getLogHeader(), "连接到网关 %v 时出现错误: %v", bot.gateway, err)
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
+		bot.mu.Lock()
 		bot.conn = conn
+		bot.mu.Unlock()
 		_ = resp.Body.Close()
 		payload, err := bot.reveive()
 		if err != nil {
 			log.Warnln(getLogHeader(), "获取心跳间隔时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
 		hb, err := payload.GetHeartbeatInterval()
 		if err != nil {
 			log.Warnln(getLogHeader(), "解析心跳间隔时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
 		payload.Op = OpCodeIdentify
@@ -271,59 +534,43 @@ func (bot *Bot) Connect() *Bot {
 		if err != nil {
 			log.Warnln(getLogHeader(), "包装 Identify 时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
 		err = bot.SendPayload(&payload)
 		if err != nil {
 			log.Warnln(getLogHeader(), "发送 Identify 时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
 		payload, err = bot.reveive()
 		if err != nil {
 			log.Warnln(getLogHeader(), "获取 EventReady 时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
-		bot.ready, bot.seq, err = payload.GetEventReady()
+		var seq uint32
+		bot.ready, seq, err = payload.GetEventReady()
 		if err != nil {
 			log.Warnln(getLogHeader(), "解析 EventReady 时出现错误:", err)
 			_ = conn.Close()
-			time.Sleep(2 * time.Second) // 等待两秒后重新连接
 			continue
 		}
+		atomic.StoreUint32(&bot.seq, seq)
+		atomic.StoreUint32(&bot.hbmissed, 0)
 		atomic.StoreUint32(&bot.heartbeat, hb)
 		break
 	}
-	clients.Store(bot.Token+"_"+strconv.Itoa(int(bot.shard[0])), bot)
+	clients.Store(bot.clientkey(), bot)
+	bot.savesession()
 	log.Infoln(getLogHeader(), "连接到网关成功, 用户名:", bot.ready.User.Username)
+	bot.lifecycle(EventTypeReady, &LifecycleEvent{})
+	bot.checkapipermissionsonce()
 	bot.hbonce.Do(func() {
 		go bot.doheartbeat()
 	})
 	return bot
 }
 
-// refreshtoken 以 Expire 为间隔刷新 Token
-func (bot *Bot) refreshtoken() {
-	for {
-		time.Sleep(time.Second * 10)
-		if atomic.LoadUint32(&bot.heartbeat) == 0 {
-			log.Warnln(getLogHeader(), "等待服务器建立连接...")
-			continue
-		}
-		time.Sleep(time.Duration(bot.expiresec) * time.Second)
-		err := bot.GetAppAccessTokenNoContext()
-		if err != nil {
-			log.Warnln(getLogHeader(), "刷新 Token 时出现错误:", err)
-		} else {
-			log.Infoln(getLogHeader(), "刷新 Token: "+bot.token+", 超时:", bot.expiresec, "秒")
-		}
-	}
-}
-
 // doheartbeat 按指定间隔进行心跳包发送
 func (bot *Bot) doheartbeat() {
 	payload := struct {
@@ -332,22 +579,38 @@ func (bot *Bot) doheartbeat() {
 	}{Op: OpCodeHeartbeat}
 	for {
 		if atomic.LoadUint32(&bot.heartbeat) == 0 {
-			time.Sleep(time.Second)
+			if !bot.sleep(time.Second) {
+				return
+			}
 			log.Warnln(getLogHeader(), "等待服务器建立连接...")
 			continue
 		}
-		time.Sleep(time.Duration(bot.heartbeat) * time.Millisecond)
-		if bot.seq == 0 {
+		if !bot.sleep(time.Duration(atomic.LoadUint32(&bot.heartbeat)) * time.Millisecond) {
+			return
+		}
+		if n := atomic.LoadUint32(&bot.hbmissed); n >= bot.HeartbeatMaxMissed {
+			log.Warnln(getLogHeader(), "连续", n, "次心跳未收到 ACK, 断开失效的连接")
+			atomic.StoreUint32(&bot.hbmissed, 0)
+			atomic.StoreUint32(&bot.heartbeat, 0)
+			bot.mu.Lock()
+			_ = bot.conn.Close() // Listen 将进入恢复流程
+			bot.mu.Unlock()
+			continue
+		}
+		seq := atomic.LoadUint32(&bot.seq)
+		if seq == 0 {
 			payload.D = nil
 		} else {
-			payload.D = &bot.seq
+			payload.D = &seq
 		}
 		bot.mu.Lock()
+		atomic.StoreInt64(&bot.hbsent, time.Now().UnixNano())
 		err := bot.conn.WriteJSON(&payload)
 		bot.mu.Unlock()
 		if err != nil {
 			log.Warnln(getLogHeader(), "发送心跳时出现错误:", err)
 		}
+		atomic.AddUint32(&bot.hbmissed, 1)
 	}
 }
 
@@ -356,33 +619,21 @@ func (bot *Bot) doheartbeat() {
 // https://bot.q.qq.com/wiki/develop/api/gateway/reference.html#_4-%E6%81%A2%E5%A4%8D%E8%BF%9E%E6%8E%A5
 func (bot *Bot) Resume() error {
 	network, address := resolveURI(bot.gateway)
-	dialer := websocket.Dialer{
-		NetDial: func(_, addr string) (net.Conn, error) {
-			if network == "unix" {
-				host, _, err := net.SplitHostPort(addr)
-				if err != nil {
-					host = addr
-				}
-				filepath, err := base64.RawURLEncoding.DecodeString(host)
-				if err == nil {
-					addr = BytesToString(filepath)
-				}
-			}
-			return net.Dial(network, addr) // support unix socket transport
-		},
-	}
-	conn, resp, err := dialer.Dial(address, http.Header{})
+	dialer := bot.wsdialer(network)
+	conn, resp, err := dialer.DialContext(bot.ctx, address, http.Header{})
 	if err != nil {
 		return err
 	}
+	bot.mu.Lock()
 	bot.conn = conn
+	bot.mu.Unlock()
 	_ = resp.Body.Close()
 	payload := WebsocketPayload{Op: OpCodeResume}
 	payload.WrapData(&struct {
 		T string `json:"token"`
 		S string `json:"session_id"`
 		Q uint32 `json:"seq"`
-	}{bot.Authorization(), bot.ready.SessionID, bot.seq})
+	}{bot.Authorization(), bot.ready.SessionID, atomic.LoadUint32(&bot.seq)})
 	return bot.SendPayload(&payload)
 }
 
@@ -391,23 +642,49 @@ func (bot *Bot) Listen() {
 	log.Infoln(getLogHeader(), "开始监听", bot.ready.User.Username, "的事件")
 	payload := WebsocketPayload{}
 	lastheartbeat := time.Now()
+	lastsave := time.Now()
 	for {
+		if bot.ctx.Err() != nil {
+			log.Infoln(getLogHeader(), "停止监听", bot.ready.User.Username, "的事件")
+			return
+		}
 		payload.Reset()
 		err := bot.conn.ReadJSON(&payload)
 		if err != nil { // reconnect
+			if bot.ctx.Err() != nil {
+				continue
+			}
 			atomic.StoreUint32(&bot.heartbeat, 0)
-			k := bot.Token + "_" + strconv.Itoa(int(bot.shard[0]))
+			k := bot.clientkey()
 			clients.Delete(k)
+			bot.lifecycle(EventTypeDisconnected, &LifecycleEvent{Error: err.Error()})
+			if bot.reshard != nil && websocket.IsCloseError(err, CloseCodeInvalidShard, CloseCodeShardingRequired) {
+				log.Warnln(getLogHeader(), bot.ready.User.Username, "的网关要求重新分片:", err)
+				bot.reshard()
+				return
+			}
 			log.Warnln(getLogHeader(), bot.ready.User.Username, "的网关连接断开, 尝试恢复:", err)
-			for {
-				time.Sleep(time.Second)
+			policy := bot.reconnectPolicy()
+			for attempt := 1; ; attempt++ {
+				if !bot.sleep(policy.Backoff(attempt)) {
+					break
+				}
+				bot.lifecycle(EventTypeReconnecting, &LifecycleEvent{Error: err.Error(), Attempt: attempt})
 				err = bot.Resume()
 				if err == nil {
 					break
 				}
-				log.Warnln(getLogHeader(), bot.ready.User.Username, "的网关连接恢复失败:", err)
+				log.Warnln(getLogHeader(), bot.ready.User.Username, "的网关连接第", attempt, "次恢复失败:", err)
+				if n := policy.MaxResumeAttempts(); n > 0 && attempt >= n {
+					log.Warnln(getLogHeader(), bot.ready.User.Username, "放弃恢复, 尝试重连...")
+					bot.lifecycle(EventTypeReconnecting, &LifecycleEvent{Error: err.Error()})
+					bot.Connect()
+					break
+				}
+			}
+			if bot.ctx.Err() == nil {
+				clients.Store(k, bot)
 			}
-			clients.Store(k, bot)
 			continue
 		}
 		log.Debug(getLogHeader(), " 接收到第 ", payload.S, " 个事件: ", payload.Op, ", 类型: ", payload.T, ", 数据: ", BytesToString(payload.D))
@@ -418,8 +695,9 @@ func (bot *Bot) Listen() {
 				continue
 			}
 			switch payload.T {
-			case "RESUMED":
+			case EventTypeResumed:
 				log.Infoln(getLogHeader(), bot.ready.User.Username, "的网关连接恢复完成")
+				bot.lifecycle(EventTypeResumed, &LifecycleEvent{})
 			default:
 				bot.processEvent(&payload)
 			}
@@ -429,10 +707,13 @@ func (bot *Bot) Listen() {
 		case OpCodeReconnect: // Receive
 			log.Warnln(getLogHeader(), "收到服务端通知重连")
 			atomic.StoreUint32(&bot.heartbeat, 0)
+			bot.lifecycle(EventTypeReconnecting, &LifecycleEvent{Error: "reconnect"})
 			bot.Connect()
 		case OpCodeInvalidSession: // Receive
 			log.Warnln(getLogHeader(), bot.ready.User.Username, "的网关连接恢复失败: InvalidSession, 尝试重连...")
 			atomic.StoreUint32(&bot.heartbeat, 0)
+			bot.deletesession()
+			bot.lifecycle(EventTypeReconnecting, &LifecycleEvent{Error: "invalid session"})
 			bot.Connect()
 		case OpCodeHello: // Receive
 			intv, err := payload.GetHeartbeatInterval()
@@ -440,16 +721,25 @@ func (bot *Bot) Listen() {
 				log.Warnln(getLogHeader(), "解析心跳间隔时出现错误:", err)
 				continue
 			}
+			atomic.StoreUint32(&bot.hbmissed, 0)
 			atomic.StoreUint32(&bot.heartbeat, intv)
 		case OpCodeHeartbeatACK: // Receive/Reply
-			log.Debugln(getLogHeader(), "收到心跳返回, 间隔:", time.Since(lastheartbeat))
-			lastheartbeat = time.Now()
+			now := time.Now()
+			atomic.StoreUint32(&bot.hbmissed, 0)
+			atomic.StoreInt64(&bot.hbacked, now.UnixNano())
+			atomic.StoreInt64(&bot.latency, now.UnixNano()-atomic.LoadInt64(&bot.hbsent))
+			log.Debugln(getLogHeader(), "收到心跳返回, 间隔:", now.Sub(lastheartbeat), ", 延迟:", bot.Latency())
+			lastheartbeat = now
 		case OpCodeHTTPCallbackACK: // Reply
 		default:
 			log.Warn(getLogHeader(), " 忽略未知事件, 序号: ", payload.S, ", Op: ", payload.Op, ", 类型: ", payload.T, ", 数据: ", BytesToString(payload.D))
 		}
 		if payload.S > bot.seq {
-			bot.seq = payload.S
+			atomic.StoreUint32(&bot.seq, payload.S)
+		}
+		if bot.SessionStore != nil && time.Since(lastsave) >= sessionSaveInterval {
+			bot.savesession()
+			lastsave = time.Now()
 		}
 	}
 }
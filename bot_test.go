@@ -0,0 +1,223 @@
+package nano
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RomiChan/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotClose(t *testing.T) {
+	var handled int32
+	bot := (&Bot{
+		Handler: &Handler{
+			OnGroupAddRobot: func(s uint32, bot *Bot, d *QQRobotStatus) {
+				time.Sleep(100 * time.Millisecond)
+				atomic.AddInt32(&handled, 1)
+			},
+		},
+	}).Init("", "", [2]byte{0, 1})
+	payload := &WebsocketPayload{Op: OpCodeDispatch, T: "GROUP_ADD_ROBOT", S: 1}
+	_ = payload.WrapData(&QQRobotStatus{GroupOpenID: "test"})
+	bot.processEvent(payload)
+	assert.NoError(t, bot.Close())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handled))
+	bot.processEvent(payload)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handled))
+	assert.False(t, bot.sleep(time.Second))
+}
+
+func TestBotCloseTimeout(t *testing.T) {
+	bot := (&Bot{
+		ShutdownTimeout: 50 * time.Millisecond,
+		Handler: &Handler{
+			OnGroupAddRobot: func(s uint32, bot *Bot, d *QQRobotStatus) {
+				time.Sleep(time.Second)
+			},
+		},
+	}).Init("", "", [2]byte{0, 1})
+	payload := &WebsocketPayload{Op: OpCodeDispatch, T: "GROUP_ADD_ROBOT", S: 1}
+	_ = payload.WrapData(&QQRobotStatus{GroupOpenID: "test"})
+	bot.processEvent(payload)
+	err := bot.Close()
+	assert.True(t, errors.Is(err, ErrShutdownTimeout))
+}
+
+// fakeGateway 本地模拟网关, ack 决定第 n 个连接的第 i 次心跳是否返回 ACK
+type fakeGateway struct {
+	*httptest.Server
+	ack     func(n, i int) bool
+	refuse  func(n int) bool // refuse 拒绝第 n 个连接
+	invalid func(n int) bool // invalid 对第 n 个连接的 Resume 返回 InvalidSession
+	conns   int32
+	ops     chan OpCode // 每个连接收到的首个 Identify/Resume
+	closes  chan int    // 客户端发送的关闭码
+}
+
+func newFakeGateway(ack func(n, i int) bool) *fakeGateway {
+	gw := &fakeGateway{ack: ack, ops: make(chan OpCode, 16), closes: make(chan int, 16)}
+	upgrader := websocket.Upgrader{}
+	gw.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gateway/bot" { // 作为 APIBase 时提供 session_start_limit
+			_, _ = w.Write([]byte(`{"url":"` + gw.url() + `","shards":1,"session_start_limit":{"total":1000,"remaining":1000,"reset_after":86400000,"max_concurrency":1}}`))
+			return
+		}
+		n := int(atomic.AddInt32(&gw.conns, 1))
+		if gw.refuse != nil && gw.refuse(n) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteJSON(&WebsocketPayload{Op: OpCodeHello, D: json.RawMessage(`{"heartbeat_interval":30}`)})
+		payload := WebsocketPayload{}
+		if conn.ReadJSON(&payload) != nil {
+			return
+		}
+		gw.ops <- payload.Op
+		switch payload.Op {
+		case OpCodeIdentify:
+			_ = conn.WriteJSON(&WebsocketPayload{Op: OpCodeDispatch, T: "READY", S: 1,
+				D: json.RawMessage(`{"session_id":"fake","user":{"id":"1","username":"nano"}}`)})
+		case OpCodeResume:
+			if gw.invalid != nil && gw.invalid(n) {
+				_ = conn.WriteJSON(&WebsocketPayload{Op: OpCodeInvalidSession, D: json.RawMessage(`false`)})
+				break
+			}
+			_ = conn.WriteJSON(&WebsocketPayload{Op: OpCodeDispatch, T: "RESUMED", S: 2, D: json.RawMessage(`""`)})
+		}
+		for i := 0; ; i++ {
+			payload.Reset()
+			if err := conn.ReadJSON(&payload); err != nil {
+				var ce *websocket.CloseError
+				if errors.As(err, &ce) {
+					select {
+					case gw.closes <- ce.Code:
+					default:
+					}
+				}
+				return
+			}
+			if payload.Op == OpCodeHeartbeat && gw.ack(n, i) {
+				_ = conn.WriteJSON(&WebsocketPayload{Op: OpCodeHeartbeatACK})
+			}
+		}
+	}))
+	return gw
+}
+
+func (gw *fakeGateway) url() string {
+	return "ws" + strings.TrimPrefix(gw.URL, "http")
+}
+
+func TestBotZombieConnection(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool {
+		return n > 1 || i < 2 // 首个连接在两次 ACK 后失效
+	})
+	defer gw.Close()
+	bot := (&Bot{AppID: "test", Token: "test"}).Init("", gw.url(), [2]byte{0, 1}).Connect()
+	defer bot.Close()
+	go bot.Listen()
+	assert.Equal(t, OpCodeIdentify, <-gw.ops)
+	select {
+	case op := <-gw.ops:
+		assert.Equal(t, OpCodeResume, op)
+	case <-time.After(5 * time.Second):
+		t.Fatal("zombie connection was not resumed")
+	}
+	assert.Greater(t, bot.Latency(), time.Duration(0))
+	assert.False(t, bot.LastHeartbeatACK().IsZero())
+}
+
+func TestBotResumeFallback(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool {
+		return n > 1 || i < 1
+	})
+	gw.refuse = func(n int) bool {
+		return n == 2 || n == 3 // 两次 Resume 均失败
+	}
+	defer gw.Close()
+	bot := (&Bot{
+		AppID: "test", Token: "test",
+		ReconnectPolicy: &ExponentialBackoff{Base: 10 * time.Millisecond, Max: 20 * time.Millisecond, MaxResume: 2},
+	}).Init("", gw.url(), [2]byte{0, 1}).Connect()
+	defer bot.Close()
+	go bot.Listen()
+	assert.Equal(t, OpCodeIdentify, <-gw.ops)
+	select {
+	case op := <-gw.ops:
+		assert.Equal(t, OpCodeIdentify, op)
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not fall back to identify")
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&gw.conns))
+}
+
+func TestWithContext(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		_, _ = w.Write([]byte(`{"id":"1","username":"nano"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := bot.WithContext(ctx).GetMyInfo()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&n))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	view := bot.WithContext(ctx)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = bot.Close()
+	}()
+	assert.Same(t, bot.ratelimiter(), view.ratelimiter()) // 视图与 bot 共享限流与缓存
+	assert.Same(t, bot.fileinfocache(), view.fileinfocache())
+	_, err = view.GetMyInfo()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithContextNoLeak(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","username":"nano"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := bot.WithContext(ctx).GetMyInfo()
+	assert.NoError(t, err)
+	n := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		_, err = bot.WithContext(ctx).WithContext(ctx).GetMyInfo()
+		assert.NoError(t, err)
+	}
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= n+2
+	}, time.Second, 10*time.Millisecond)
+}
@@ -22,6 +22,8 @@ func main() {
 	f.WriteString(`// Code generated by codegen/context. DO NOT EDIT.
 
 package nano
+
+import "context"
 `)
 	err = fs.WalkDir(os.DirFS("./"), ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -41,37 +43,36 @@ package nano
 			if strings.Contains(define[3], "NoContext") {
 				continue
 			}
+			funcname, after, _ := strings.Cut(define[3], "(")
+			paras, _, _ := strings.Cut(after, ")")
+			args := callargs(paras)
 			f.WriteString(define[1])          // 注释
 			f.WriteString("func (ctx *Ctx) ") // 函数声明
 			f.WriteString(define[3])
 			f.WriteString(" {\n")
 			// 函数调用
 			f.WriteString("\treturn ctx.caller.")
-			funcname, after, _ := strings.Cut(define[3], "(")
 			f.WriteString(funcname)
 			f.WriteString("(")
-			after, _, _ = strings.Cut(after, ")")
-			paras := strings.Split(after, ", ")
-			switch len(paras) {
-			case 0:
-			case 1:
-				name, def, _ := strings.Cut(paras[0], " ")
-				f.WriteString(name)
-				if strings.Contains(def, "...") {
-					f.WriteString("...")
-				}
-			default:
-				for _, para := range paras[:len(paras)-1] {
-					name, _, _ := strings.Cut(para, " ")
-					f.WriteString(name)
-					f.WriteString(", ")
-				}
-				name, def, _ := strings.Cut(paras[len(paras)-1], " ")
-				f.WriteString(name)
-				if strings.Contains(def, "...") {
-					f.WriteString("...")
-				}
+			f.WriteString(args)
+			f.WriteString(")\n}\n")
+			// 带 context 的版本
+			f.WriteString("\n// ")
+			f.WriteString(funcname)
+			f.WriteString("WithContext 同 ")
+			f.WriteString(funcname)
+			f.WriteString(", c 结束时取消请求\n")
+			f.WriteString("func (ctx *Ctx) ")
+			f.WriteString(funcname)
+			f.WriteString("WithContext(c context.Context")
+			if paras != "" {
+				f.WriteString(", ")
 			}
+			f.WriteString(after)
+			f.WriteString(" {\n\treturn ctx.caller.WithContext(c).")
+			f.WriteString(funcname)
+			f.WriteString("(")
+			f.WriteString(args)
 			f.WriteString(")\n}\n")
 		}
 		f.WriteString("\n/* ^^^^^^^^^^^^^^^^^^^^ 生成自文件 ")
@@ -83,3 +84,22 @@ package nano
 		panic(err)
 	}
 }
+
+// callargs 将形参列表 paras 转为调用时的实参
+func callargs(paras string) string {
+	if paras == "" {
+		return ""
+	}
+	sb := strings.Builder{}
+	for i, para := range strings.Split(paras, ", ") {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		name, def, _ := strings.Cut(para, " ")
+		sb.WriteString(name)
+		if strings.Contains(def, "...") {
+			sb.WriteString("...")
+		}
+	}
+	return sb.String()
+}
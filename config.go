@@ -0,0 +1,313 @@
+package nano
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrNoBots       = errors.New("no bots")
+	ErrEmptyValue   = errors.New("empty value")
+	ErrUndefinedEnv = errors.New("undefined environment variable")
+)
+
+// CommandPrefix CommandRule 识别的命令前缀, 默认 /
+var CommandPrefix = "/"
+
+// envpattern 匹配 ${ENV}
+var envpattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Config 配置文件, 支持 YAML 与 JSON, 字符串中的 ${ENV} 将被替换为环境变量
+type Config struct {
+	APIBase       string               `yaml:"APIBase"`       // APIBase 未单独设置的 bot 使用的 OpenAPI 域名
+	LogLevel      string               `yaml:"LogLevel"`      // LogLevel logrus 日志等级
+	CommandPrefix string               `yaml:"CommandPrefix"` // CommandPrefix 见 nano.CommandPrefix
+	SuperUsers    []string             `yaml:"SuperUsers"`    // SuperUsers 追加到每个 bot 的超级用户
+	Bots          []*Bot               `yaml:"Bots"`          // Bots 要运行的 bot
+	Plugins       map[string]yaml.Node `yaml:"Plugins"`       // Plugins 以 Register 的 service 为键的插件配置
+}
+
+// ConfigError 配置文件中 Key 处的错误
+type ConfigError struct {
+	Key  string // Key 如 Bots[0].AppID
+	Line int    // Line 所在行, 0 为未知
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString("config: ")
+	sb.WriteString(e.Key)
+	if e.Line > 0 {
+		sb.WriteString(" (line ")
+		sb.WriteString(strconv.Itoa(e.Line))
+		sb.WriteString(")")
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	configmu sync.RWMutex
+	config   *Config // config 最近一次 LoadConfig 的结果
+)
+
+// LoadConfig 读取并校验配置文件, 其插件配置可由 Engine.Config 读取
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	root := &yaml.Node{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		root, err = jsonnode(data)
+	} else {
+		err = yaml.Unmarshal(data, root)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = expandenv(root, "")
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	err = root.Decode(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.validate(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range cfg.Bots {
+		if b.APIBase == "" {
+			b.APIBase = cfg.APIBase
+		}
+		b.SuperUsers = append(b.SuperUsers, cfg.SuperUsers...)
+	}
+	configmu.Lock()
+	config = cfg
+	configmu.Unlock()
+	return cfg, nil
+}
+
+// RunConfig 读取配置文件, 应用全局设置并运行其中所有 bot 直到 ctx 结束
+func RunConfig(ctx context.Context, path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	cfg.apply()
+	return RunContext(ctx, cfg.Bots...)
+}
+
+// apply 应用全局设置
+func (cfg *Config) apply() {
+	if cfg.LogLevel != "" {
+		lv, _ := log.ParseLevel(cfg.LogLevel) // 已在 validate 校验
+		log.SetLevel(lv)
+	}
+	if cfg.CommandPrefix != "" {
+		CommandPrefix = cfg.CommandPrefix
+	}
+}
+
+// validate 校验配置, 错误指向 root 中对应的键
+func (cfg *Config) validate(root *yaml.Node) error {
+	if cfg.LogLevel != "" {
+		if _, err := log.ParseLevel(cfg.LogLevel); err != nil {
+			return &ConfigError{Key: "LogLevel", Line: nodeline(root, "LogLevel"), Err: err}
+		}
+	}
+	if len(cfg.Bots) == 0 {
+		return &ConfigError{Key: "Bots", Line: nodeline(root, "Bots"), Err: ErrNoBots}
+	}
+	for i, b := range cfg.Bots {
+		key := "Bots[" + strconv.Itoa(i) + "]"
+		if b == nil {
+			return &ConfigError{Key: key, Line: nodeline(root, "Bots", i), Err: ErrEmptyValue}
+		}
+		if b.AppID == "" {
+			return &ConfigError{Key: key + ".AppID", Line: nodeline(root, "Bots", i, "AppID"), Err: ErrEmptyValue}
+		}
+		if b.Token == "" {
+			return &ConfigError{Key: key + ".Token", Line: nodeline(root, "Bots", i, "Token"), Err: ErrEmptyValue}
+		}
+		if b.ShardCount != 0 && b.ShardIndex >= b.ShardCount {
+			return &ConfigError{
+				Key: key + ".ShardIndex", Line: nodeline(root, "Bots", i, "ShardIndex"),
+				Err: errors.New("shard index " + strconv.Itoa(int(b.ShardIndex)) + " >= shard count " + strconv.Itoa(int(b.ShardCount))),
+			}
+		}
+	}
+	return nil
+}
+
+// expandenv 将 n 下所有字符串中的 ${ENV} 替换为环境变量
+func expandenv(n *yaml.Node, key string) error {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i, c := range n.Content {
+			k := key
+			if n.Kind == yaml.SequenceNode {
+				k += "[" + strconv.Itoa(i) + "]"
+			}
+			if err := expandenv(c, k); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i].Value
+			if key != "" {
+				k = key + "." + k
+			}
+			if err := expandenv(n.Content[i+1], k); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		var err error
+		n.Value = envpattern.ReplaceAllStringFunc(n.Value, func(s string) string {
+			name := s[2 : len(s)-1]
+			v, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = &ConfigError{Key: key, Line: n.Line, Err: errors.New(ErrUndefinedEnv.Error() + " " + name)}
+			}
+			return v
+		})
+		return err
+	}
+	return nil
+}
+
+// jsonnode 将 json 解析为与 yaml.Unmarshal 结果相同结构的 yaml.Node, 保留键的顺序与所在行
+func jsonnode(data []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := decodejsonnode(dec, data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid json: data after top-level value")
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Line: n.Line, Column: 1, Content: []*yaml.Node{n}}, nil
+}
+
+// decodejsonnode 从 dec 读取一个值
+func decodejsonnode(dec *json.Decoder, data []byte) (*yaml.Node, error) {
+	off := int(dec.InputOffset())
+	for off < len(data) && strings.IndexByte(" \t\r\n,:", data[off]) >= 0 { // 跳到下一个 token
+		off++
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &yaml.Node{Kind: yaml.ScalarNode, Line: 1 + bytes.Count(data[:off], []byte{'\n'})}
+	switch v := tok.(type) {
+	case json.Delim:
+		n.Kind, n.Tag = yaml.MappingNode, "!!map"
+		if v == '[' {
+			n.Kind, n.Tag = yaml.SequenceNode, "!!seq"
+		}
+		for dec.More() {
+			c, err := decodejsonnode(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, c)
+		}
+		if _, err = dec.Token(); err != nil { // 结束符
+			return nil, err
+		}
+	case string:
+		n.Tag, n.Value = "!!str", v
+	case json.Number:
+		n.Tag, n.Value = "!!int", v.String()
+		if strings.ContainsAny(n.Value, ".eE") {
+			n.Tag = "!!float"
+		}
+	case bool:
+		n.Tag, n.Value = "!!bool", strconv.FormatBool(v)
+	case nil:
+		n.Tag, n.Value = "!!null", "null"
+	}
+	return n, nil
+}
+
+// nodeline 按 path (string 为键, int 为下标) 查找节点所在行, 找不到时返回最近的上级节点所在行
+func nodeline(root *yaml.Node, path ...any) int {
+	n := root
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	line := n.Line
+	for _, p := range path {
+		var next *yaml.Node
+		switch k := p.(type) {
+		case string:
+			if n.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(n.Content); i += 2 {
+					if n.Content[i].Value == k {
+						next = n.Content[i+1]
+						line = n.Content[i].Line
+						break
+					}
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && k < len(n.Content) {
+				next = n.Content[k]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			return line
+		}
+		n = next
+	}
+	return line
+}
+
+// PluginConfig 将最近一次 LoadConfig 中 service 的插件配置解析到 ptr, 没有配置时不修改 ptr
+func PluginConfig(service string, ptr any) error {
+	configmu.RLock()
+	defer configmu.RUnlock()
+	if config == nil {
+		return nil
+	}
+	n, ok := config.Plugins[service]
+	if !ok {
+		return nil
+	}
+	err := n.Decode(ptr)
+	if err != nil {
+		return &ConfigError{Key: "Plugins." + service, Line: n.Line, Err: err}
+	}
+	return nil
+}
+
+// Config 将本插件的配置解析到 ptr, 见 PluginConfig
+func (e *Engine) Config(ptr any) error {
+	return PluginConfig(e.service, ptr)
+}
@@ -0,0 +1,98 @@
+package nano
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	p := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(p, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("NANO_TEST_SECRET", "s3cret")
+	p := writeConfig(t, "config.yml", `
+APIBase: https://sandbox.api.sgroup.qq.com
+LogLevel: debug
+SuperUsers: [root]
+Bots:
+  - AppID: "123"
+    Token: token
+    Secret: ${NANO_TEST_SECRET}
+    Timeout: 30s
+    Intents: 1
+Plugins:
+  echo:
+    Reply: echo ${NANO_TEST_SECRET}
+`)
+	cfg, err := LoadConfig(p)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Bots, 1)
+	b := cfg.Bots[0]
+	assert.Equal(t, "s3cret", b.Secret)
+	assert.Equal(t, 30*time.Second, b.Timeout)
+	assert.Equal(t, SandboxAPI, b.APIBase)
+	assert.Equal(t, []string{"root"}, b.SuperUsers)
+	pc := struct {
+		Reply string `yaml:"Reply"`
+	}{}
+	assert.NoError(t, PluginConfig("echo", &pc))
+	assert.Equal(t, "echo s3cret", pc.Reply)
+	assert.NoError(t, PluginConfig("nonexist", &pc))
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	p := writeConfig(t, "config.json", "{\n\t\"Bots\": [\n\t\t{\"AppID\": \"123\", \"Token\": \"to\\tken\", \"Intents\": 513}\n\t]\n}\n")
+	cfg, err := LoadConfig(p)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", cfg.Bots[0].AppID)
+	assert.Equal(t, "to\tken", cfg.Bots[0].Token)
+	assert.Equal(t, uint32(513), cfg.Bots[0].Intents)
+
+	p = writeConfig(t, "config.json", "{\n\t\"Bots\": [\n\t\t{\"AppID\": \"123\", \"Token\": \"token\"},\n\t\t{\"AppID\": \"\", \"Token\": \"token\"}\n\t]\n}\n")
+	_, err = LoadConfig(p)
+	var cerr *ConfigError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, "Bots[1].AppID", cerr.Key)
+	assert.Equal(t, 4, cerr.Line)
+
+	p = writeConfig(t, "config.json", `{"Bots": []} {}`)
+	_, err = LoadConfig(p)
+	assert.Error(t, err)
+}
+
+func TestLoadConfigError(t *testing.T) {
+	p := writeConfig(t, "config.yml", `
+Bots:
+  - AppID: "123"
+    Token: token
+  - AppID: ""
+    Token: token
+`)
+	_, err := LoadConfig(p)
+	var cerr *ConfigError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, "Bots[1].AppID", cerr.Key)
+	assert.Equal(t, 5, cerr.Line)
+	assert.True(t, errors.Is(err, ErrEmptyValue))
+
+	p = writeConfig(t, "config.yml", `
+Bots:
+  - AppID: "123"
+    Token: ${NANO_TEST_UNDEFINED}
+`)
+	_, err = LoadConfig(p)
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, "Bots[0].Token", cerr.Key)
+	assert.Equal(t, 4, cerr.Line)
+}
@@ -1,22 +1,26 @@
 package nano
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"strconv"
-	"strings"
 	"sync"
 
-	base14 "github.com/fumiama/go-base16384"
-	"github.com/fumiama/imoto"
 	"github.com/sirupsen/logrus"
 )
 
 //go:generate go run codegen/context/main.go
 
+var (
+	// ErrNoMessageContext 当前 Ctx 不是由消息触发, 无法确定撤回的场景
+	ErrNoMessageContext = errors.New("no message in context")
+	// ErrNoInteractionContext 当前 Ctx 不是由互动事件触发
+	ErrNoInteractionContext = errors.New("no interaction in context")
+	// ErrKeyboardWithoutMarkdown 消息按钮须与 markdown 一同发送
+	ErrKeyboardWithoutMarkdown = errors.New("keyboard without markdown")
+)
+
 type Ctx struct {
 	Event
 	State
@@ -85,29 +89,38 @@ func (ctx *Ctx) CheckSession() Rule {
 	}
 }
 
-var imotoken = "f7f06a63b8c111df0d4faa256cd5ba35cb98678ee8274923576d0416b52fe768"
-
-// Send 发送一批消息
+// Send 发送一批消息, 其中的 Markdown 与 Keyboard 在最后合并为一条 markdown 消息
 func (ctx *Ctx) Send(messages Messages) (m []*Message, err error) {
 	isnextreply := false
 	textlist := []any{}
-	var reply *Message
+	var (
+		reply    *Message
+		markdown *MessageMarkdown
+		keyboard *KeyboardBuilder
+	)
+	for _, msg := range messages {
+		switch msg.Type {
+		case MessageSegmentTypeMarkdown:
+			markdown = msg.Markdown
+		case MessageSegmentTypeKeyboard:
+			keyboard = msg.Keyboard
+		}
+	}
+	var mdpost *MessagePost // 发送任何消息前先检查并构建 markdown 与按钮
+	if markdown != nil {
+		mdpost, err = ctx.markdownpost(markdown, keyboard)
+		if err != nil {
+			return
+		}
+	} else if keyboard != nil {
+		return nil, ErrKeyboardWithoutMarkdown
+	}
 	for _, msg := range messages {
 		switch msg.Type {
 		case MessageSegmentTypeText:
 			textlist = append(textlist, msg.Data)
-		case MessageSegmentTypeImage:
-			reply, err = ctx.SendImage(msg.Data, isnextreply, textlist...)
-			if isnextreply {
-				isnextreply = false
-			}
-			textlist = textlist[:0]
-			m = append(m, reply)
-			if err != nil {
-				return
-			}
-		case MessageSegmentTypeImageBytes:
-			reply, err = ctx.SendImageBytes(StringToBytes(msg.Data), isnextreply, textlist...)
+		case MessageSegmentTypeImage, MessageSegmentTypeImageBytes:
+			reply, err = ctx.SendImageOf(msg.source(), isnextreply, textlist...)
 			if isnextreply {
 				isnextreply = false
 			}
@@ -122,19 +135,11 @@ func (ctx *Ctx) Send(messages Messages) (m []*Message, err error) {
 			if !ctx.IsQQ {
 				continue
 			}
-			fp := &FilePost{
-				URL: msg.Data,
-			}
-			if msg.Type == MessageSegmentTypeAudio {
-				fp.Type = FileTypeAudio
-			} else if msg.Type == MessageSegmentTypeVideo {
-				fp.Type = FileTypeVideo
-			}
-			if OnlyQQGroup(ctx) {
-				reply, err = ctx.PostFileToQQGroup(ctx.Message.ChannelID, fp)
-			} else if OnlyQQPrivate(ctx) {
-				reply, err = ctx.PostFileToQQUser(ctx.Message.Author.ID, fp)
+			tp := FileType(FileTypeAudio)
+			if msg.Type == MessageSegmentTypeVideo {
+				tp = FileTypeVideo
 			}
+			reply, err = ctx.uploadqqmedia(tp, msg.source())
 			if err != nil {
 				return
 			}
@@ -151,6 +156,14 @@ func (ctx *Ctx) Send(messages Messages) (m []*Message, err error) {
 	}
 	if len(textlist) > 0 {
 		reply, err = ctx.SendPlainMessage(isnextreply, textlist...)
+		isnextreply = false
+		m = append(m, reply)
+		if err != nil {
+			return
+		}
+	}
+	if mdpost != nil {
+		reply, err = ctx.Post(isnextreply, mdpost)
 		m = append(m, reply)
 	}
 	return
@@ -164,7 +177,9 @@ func (ctx *Ctx) SendChain(message ...MessageSegment) (m []*Message, err error) {
 // Post 发送消息到对方
 func (ctx *Ctx) Post(replytosender bool, post *MessagePost) (reply *Message, err error) {
 	msg := ctx.Message
-	if msg != nil {
+	if it, ok := ctx.Value.(*Interaction); ok && msg != nil {
+		post.ReplyEventID = it.ID // 互动事件以 event_id 被动回复
+	} else if msg != nil {
 		post.ReplyMessageID = msg.ID
 		if OnlyGuild(ctx) && replytosender {
 			post.MessageReference = &MessageReference{
@@ -199,7 +214,11 @@ func (ctx *Ctx) Post(replytosender bool, post *MessagePost) (reply *Message, err
 			reply, err = ctx.PostMessageToQQUser(msg.ChannelID, post)
 		}
 		if err == nil {
-			logtriggeredmessages(msg.ID, "") // only to log message seq
+			id := "" // 至少记录一条以计算 msg_seq
+			if reply != nil {
+				id = reply.ID
+			}
+			logtriggeredmessages(msg.ID, id)
 		}
 		return
 	}
@@ -209,6 +228,76 @@ func (ctx *Ctx) Post(replytosender bool, post *MessagePost) (reply *Message, err
 	return
 }
 
+// Recall 撤回本会话中机器人发送的消息 messageid, 按场景选择与 Post 相同的接口
+func (ctx *Ctx) Recall(messageid string) error {
+	msg := ctx.Message
+	if msg == nil {
+		return ErrNoMessageContext
+	}
+	switch {
+	case OnlyDirect(ctx):
+		return ctx.caller.DeleteMessageOfUser(msg.GuildID, messageid, false)
+	case OnlyChannel(ctx):
+		return ctx.caller.DeleteMessageInChannel(msg.ChannelID, messageid, false)
+	case OnlyQQGroup(ctx):
+		return ctx.caller.DeleteMessageInQQGroup(msg.ChannelID, messageid)
+	case OnlyQQPrivate(ctx):
+		if msg.Author == nil {
+			return ErrNoMessageContext
+		}
+		return ctx.caller.DeleteMessageOfQQUser(msg.Author.ID, messageid)
+	}
+	return ErrNoMessageContext
+}
+
+// RecallTriggeredMessages 撤回 GetTriggeredMessages(id) 记录的全部回复, 返回所有出错的原因
+func (ctx *Ctx) RecallTriggeredMessages(id string) error {
+	var errs []error
+	for _, reply := range GetTriggeredMessages(id) {
+		if reply == "" {
+			continue
+		}
+		if err := ctx.Recall(reply); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AckInteraction 回应触发本次事件的互动, 每个互动仅回应一次, 未回应的互动将在匹配结束后以成功回应
+func (ctx *Ctx) AckInteraction(code InteractionResultCode) error {
+	it, ok := ctx.Value.(*Interaction)
+	if !ok {
+		return ErrNoInteractionContext
+	}
+	return ctx.caller.AckInteraction(it, code)
+}
+
+// SendMarkdown 发送 markdown 消息到对方, kb 可为空
+func (ctx *Ctx) SendMarkdown(replytosender bool, md *MessageMarkdown, kb *KeyboardBuilder) (*Message, error) {
+	post, err := ctx.markdownpost(md, kb)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Post(replytosender, post)
+}
+
+// markdownpost 构建带有 kb 按钮的 markdown 消息, kb 可为空
+func (ctx *Ctx) markdownpost(md *MessageMarkdown, kb *KeyboardBuilder) (*MessagePost, error) {
+	post := &MessagePost{Markdown: md}
+	if kb != nil {
+		keyboard, err := kb.Build()
+		if err != nil {
+			return nil, err
+		}
+		if keyboard.Content != nil {
+			keyboard.Content.BotAppID, _ = strconv.Atoi(ctx.caller.AppID)
+		}
+		post.KeyBoard = keyboard
+	}
+	return post, nil
+}
+
 // SendPlainMessage 发送纯文本消息到对方
 func (ctx *Ctx) SendPlainMessage(replytosender bool, printable ...any) (*Message, error) {
 	return ctx.Post(replytosender, &MessagePost{
@@ -216,84 +305,41 @@ func (ctx *Ctx) SendPlainMessage(replytosender bool, printable ...any) (*Message
 	})
 }
 
-// SendImage 发送带图片消息到对方
-func (ctx *Ctx) SendImage(file string, replytosender bool, caption ...any) (reply *Message, err error) {
+// SendImage 发送带图片消息到对方, file 的格式同 MediaOf
+func (ctx *Ctx) SendImage(file string, replytosender bool, caption ...any) (*Message, error) {
+	return ctx.SendImageOf(MediaOf(file), replytosender, caption...)
+}
+
+// SendImageBytes 发送带图片消息到对方
+func (ctx *Ctx) SendImageBytes(data []byte, replytosender bool, caption ...any) (*Message, error) {
+	return ctx.SendImageOf(MediaBytes(data), replytosender, caption...)
+}
+
+// SendImageOf 发送来自 src 的带图片消息到对方
+func (ctx *Ctx) SendImageOf(src *MediaSource, replytosender bool, caption ...any) (*Message, error) {
 	post := &MessagePost{
 		Content: HideURL(fmt.Sprint(caption...)),
 	}
 
 	if OnlyQQ(ctx) {
-		if strings.HasPrefix(file, "file:///") {
-			data, err := os.ReadFile(file[8:])
-			if err != nil {
-				return nil, err
-			}
-			return ctx.SendImageBytes(data, replytosender, caption...)
-		}
-		if strings.HasPrefix(file, "base64://") {
-			data, err := base64.StdEncoding.DecodeString(file[9:])
-			if err != nil {
-				return nil, err
-			}
-			return ctx.SendImageBytes(data, replytosender, caption...)
-		}
-		if strings.HasPrefix(file, "base16384://") {
-			data := base14.DecodeFromString(file[12:])
-			if len(data) == 0 {
-				return nil, errors.New("invalid base16384 image")
-			}
-			return ctx.SendImageBytes(data, replytosender, caption...)
-		}
-		fp := &FilePost{
-			Type: FileTypeImage,
-			URL:  file,
-		}
-		/*if len(caption) > 0 {
-			_, _ = ctx.SendPlainMessage(replytosender, caption...)
-		}*/
 		if post.Content == "" {
 			post.Content = " "
 		}
-		if OnlyQQGroup(ctx) {
-			reply, err = ctx.PostFileToQQGroup(ctx.Message.ChannelID, fp)
-		} else if OnlyQQPrivate(ctx) {
-			reply, err = ctx.PostFileToQQUser(ctx.Message.Author.ID, fp)
-		}
+		reply, err := ctx.uploadqqmedia(FileTypeImage, src)
 		if err != nil {
-			return
+			return nil, err
 		}
 		logrus.Infoln(getLogHeader(), "=> 上传:", reply)
 		post.Media = &MessageMedia{FileInfo: reply.FileInfo}
+	} else if u := src.URL(); u != "" {
+		post.Image = u
 	} else {
-		if strings.HasPrefix(file, "http") {
-			post.Image = file
-		} else {
-			post.ImageFile = file
-		}
+		post.ImageSource = src
 	}
 
 	return ctx.Post(replytosender, post)
 }
 
-// SendImageBytes 发送带图片消息到对方
-func (ctx *Ctx) SendImageBytes(data []byte, replytosender bool, caption ...any) (*Message, error) {
-	if OnlyQQ(ctx) {
-		file, _, _, err := imoto.Bed(imotoken, data)
-		if err != nil {
-			return nil, err
-		}
-		return ctx.SendImage(file, replytosender, caption...)
-	}
-
-	post := &MessagePost{
-		Content: HideURL(fmt.Sprint(caption...)),
-	}
-
-	post.ImageBytes = data
-
-	return ctx.Post(replytosender, post)
-}
-
 // Echo 向自身分发虚拟事件
 func (ctx *Ctx) Echo(payload *WebsocketPayload) {
 	ctx.caller.processEvent(payload)
@@ -1,5 +1,11 @@
 package nano
 
+import (
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
 //go:generate go run codegen/engine/main.go
 
 // 生成空引擎
@@ -22,6 +28,7 @@ type Engine struct {
 	prio        int
 	service     string
 	datafolder  string
+	apis        []APIPermissionDemandIdentify
 }
 
 // Delete 移除该 Engine 注册的所有 Matchers
@@ -31,6 +38,88 @@ func (e *Engine) Delete() {
 	}
 }
 
+// OnButton 回调按钮触发器, 匹配 action.data 以 prefix 开头的按钮
+func OnButton(prefix string, rules ...Rule) *Matcher {
+	return defaultEngine.OnButton(prefix, rules...)
+}
+
+// OnButton 回调按钮触发器, 匹配 action.data 以 prefix 开头的按钮
+func (e *Engine) OnButton(prefix string, rules ...Rule) *Matcher {
+	matcher := &Matcher{
+		Type:   "InteractionCreate",
+		Rules:  append([]Rule{ButtonRule(prefix)}, rules...),
+		Engine: e,
+	}
+	e.matchers = append(e.matchers, matcher)
+	return StoreMatcher(matcher)
+}
+
+// RequireAPI 声明插件依赖的 OpenAPI 接口, bot 连接后将检查其在各频道内是否已授权
+//
+// path 与 GetAPIPermissionsOfGuild 返回的相同, ex. /guilds/{guild_id}/members/{user_id}
+func (e *Engine) RequireAPI(method, path string) *Engine {
+	enmu.Lock()
+	defer enmu.Unlock()
+	e.apis = append(e.apis, APIPermissionDemandIdentify{Path: path, Method: method})
+	return e
+}
+
+// requiredapis 已注册插件依赖的接口与依赖它的插件
+func requiredapis() map[APIPermissionDemandIdentify][]string {
+	required := map[APIPermissionDemandIdentify][]string{}
+	enmu.RLock()
+	defer enmu.RUnlock()
+	for _, api := range defaultEngine.apis {
+		required[api] = append(required[api], "default")
+	}
+	for service, e := range enmap {
+		for _, api := range e.apis {
+			required[api] = append(required[api], service)
+		}
+	}
+	for _, services := range required {
+		sort.Strings(services)
+	}
+	return required
+}
+
+// checkapipermissionsonce 在后台检查一次接口权限, 同一 owner 的所有分片 (含重新分片后的) 共用一次检查
+func (bot *Bot) checkapipermissionsonce() {
+	bot.tokenholder().pmonce.Do(func() {
+		required := requiredapis()
+		if len(required) > 0 {
+			go bot.checkapipermissions(required)
+		}
+	})
+}
+
+// checkapipermissions 检查 required 在 bot 加入的各频道内是否已授权, 未授权时警告
+func (bot *Bot) checkapipermissions(required map[APIPermissionDemandIdentify][]string) {
+	guilds, err := bot.IterMyGuilds(100).All(bot.context())
+	if err != nil {
+		log.Warnln(getLogHeader(), "检查接口权限时获取频道列表出现错误:", err)
+		return
+	}
+	for _, g := range guilds {
+		perms, err := bot.GetAPIPermissionsOfGuild(g.ID)
+		if err != nil {
+			log.Warnln(getLogHeader(), "获取频道", g.Name, "的接口权限时出现错误:", err)
+			continue
+		}
+		granted := make(map[APIPermissionDemandIdentify]bool, len(perms))
+		for _, p := range perms {
+			if p.AuthStatus == 1 {
+				granted[APIPermissionDemandIdentify{Path: p.Path, Method: p.Method}] = true
+			}
+		}
+		for api, services := range required {
+			if !granted[api] {
+				log.Warnln(getLogHeader(), "频道", g.Name, "("+g.ID+") 未授权接口", api.Method, api.Path+", 插件", services, "可能无法正常工作")
+			}
+		}
+	}
+}
+
 // UsePreHandler 向该 Engine 添加新 PreHandler(Rule),
 // 会在 Rule 判断前触发，如果 preHandler
 // 没有通过，则 Rule, Matcher 不会触发
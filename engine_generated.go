@@ -134,6 +134,12 @@ func (e *Engine) OnAudioOrLiveChannelMemberExit(rules ...Rule) *Matcher { return
 // OnAudioOrLiveChannelMemberExit ...
 func OnAudioOrLiveChannelMemberExit(rules ...Rule) *Matcher { return On("AudioOrLiveChannelMemberExit", rules...) }
 
+// OnInteractionCreate ...
+func (e *Engine) OnInteractionCreate(rules ...Rule) *Matcher { return e.On("InteractionCreate", rules...) }
+
+// OnInteractionCreate ...
+func OnInteractionCreate(rules ...Rule) *Matcher { return On("InteractionCreate", rules...) }
+
 // OnMessageAuditPass ...
 func (e *Engine) OnMessageAuditPass(rules ...Rule) *Matcher { return e.On("MessageAuditPass", rules...) }
 
@@ -218,6 +224,36 @@ func (e *Engine) OnAudioOffMic(rules ...Rule) *Matcher { return e.On("AudioOffMi
 // OnAudioOffMic ...
 func OnAudioOffMic(rules ...Rule) *Matcher { return On("AudioOffMic", rules...) }
 
+// OnReady ...
+func (e *Engine) OnReady(rules ...Rule) *Matcher { return e.On("Ready", rules...) }
+
+// OnReady ...
+func OnReady(rules ...Rule) *Matcher { return On("Ready", rules...) }
+
+// OnResumed ...
+func (e *Engine) OnResumed(rules ...Rule) *Matcher { return e.On("Resumed", rules...) }
+
+// OnResumed ...
+func OnResumed(rules ...Rule) *Matcher { return On("Resumed", rules...) }
+
+// OnDisconnected ...
+func (e *Engine) OnDisconnected(rules ...Rule) *Matcher { return e.On("Disconnected", rules...) }
+
+// OnDisconnected ...
+func OnDisconnected(rules ...Rule) *Matcher { return On("Disconnected", rules...) }
+
+// OnReconnecting ...
+func (e *Engine) OnReconnecting(rules ...Rule) *Matcher { return e.On("Reconnecting", rules...) }
+
+// OnReconnecting ...
+func OnReconnecting(rules ...Rule) *Matcher { return On("Reconnecting", rules...) }
+
+// OnTokenRefreshed ...
+func (e *Engine) OnTokenRefreshed(rules ...Rule) *Matcher { return e.On("TokenRefreshed", rules...) }
+
+// OnTokenRefreshed ...
+func OnTokenRefreshed(rules ...Rule) *Matcher { return On("TokenRefreshed", rules...) }
+
 // OnMessagePrefix ...
 func OnMessagePrefix(prefix string, rules ...Rule) *Matcher {
 	return defaultEngine.OnMessagePrefix(prefix, rules...)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"strings"
+	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -20,12 +21,46 @@ type Event struct {
 	value reflect.Value
 }
 
+// goevent 在 bot 未关闭时异步执行 f 并计入处理中的事件
+func (bot *Bot) goevent(f func()) bool {
+	bot.evmu.RLock()
+	defer bot.evmu.RUnlock()
+	if bot.closing {
+		return false
+	}
+	bot.wg.Add(1)
+	go func() {
+		defer bot.wg.Done()
+		f()
+	}()
+	return true
+}
+
+// isclosing bot 是否已开始关闭
+func (bot *Bot) isclosing() bool {
+	bot.evmu.RLock()
+	defer bot.evmu.RUnlock()
+	return bot.closing
+}
+
 // processEvent 处理需要关注的业务事件
 func (bot *Bot) processEvent(payload *WebsocketPayload) {
 	tp := UnderlineToCamel(payload.T)
+	if bot.isclosing() {
+		log.Debugln(getLogHeader(), "bot 已关闭, 忽略", tp, "事件")
+		return
+	}
 	if bot.Handler != nil {
 		ev, ok := bot.handlers[tp]
 		if !ok {
+			if tp == "InteractionCreate" { // 未注册处理函数时仍需回应
+				it := &Interaction{}
+				if err := json.Unmarshal(payload.D, it); err == nil {
+					bot.goevent(func() {
+						bot.autoack(it)
+					})
+				}
+			}
 			return
 		}
 		log.Debugln(getLogHeader(), "使用 handlers 处理", tp, "事件")
@@ -35,7 +70,13 @@ func (bot *Bot) processEvent(payload *WebsocketPayload) {
 			log.Warnln(getLogHeader(), "解析", tp, "事件时出现错误:", err)
 			return
 		}
-		go ev.h(payload.S, bot, x.UnsafePointer())
+		seq, ptr := payload.S, x.UnsafePointer()
+		bot.goevent(func() {
+			ev.h(seq, bot, ptr)
+			if tp == "InteractionCreate" {
+				bot.autoack((*Interaction)(ptr))
+			}
+		})
 		return
 	}
 	ctx := &Ctx{
@@ -64,7 +105,7 @@ func (bot *Bot) processEvent(payload *WebsocketPayload) {
 	}
 	matcherLock.RLock()
 	n := len(matcherMap[tp])
-	if n == 0 {
+	if n == 0 && tp != "InteractionCreate" { // 互动事件无 matcher 时仍需回应
 		matcherLock.RUnlock()
 		return
 	}
@@ -95,6 +136,11 @@ func (bot *Bot) processEvent(payload *WebsocketPayload) {
 			ctx.Message.ChannelID = ctx.Message.GroupOpenID
 		}
 		log.Infoln(getLogHeader(), "=>", ctx.Message)
+	case "InteractionCreate":
+		it := (*Interaction)(x.UnsafePointer())
+		ctx.Message = it.message()
+		ctx.IsQQ = it.ChatType != InteractionChatTypeGuild
+		ctx.IsToMe = true
 	case "MessageDelete":
 		mdl := (*MessageDelete)(x.UnsafePointer())
 		opmember, err := ctx.GetGuildMemberOf(mdl.Message.GuildID, mdl.OpUser.ID)
@@ -110,7 +156,27 @@ func (bot *Bot) processEvent(payload *WebsocketPayload) {
 		ctx.Message.Author = opmember.User
 		log.Infoln(getLogHeader(), "x>", mdl)
 	}
-	go match(ctx, matchers)
+	bot.goevent(func() {
+		match(ctx, matchers)
+		if tp == "InteractionCreate" {
+			bot.autoack(ctx.Value.(*Interaction))
+		}
+	})
+}
+
+// AckInteraction 回应互动事件 it, 每个互动仅回应一次, 重复调用时忽略
+func (bot *Bot) AckInteraction(it *Interaction, code InteractionResultCode) error {
+	if !atomic.CompareAndSwapUint32(&it.acked, 0, 1) {
+		return nil
+	}
+	return bot.PutInteractionResult(it.ID, code)
+}
+
+// autoack 以成功回应尚未回应的互动事件
+func (bot *Bot) autoack(it *Interaction) {
+	if err := bot.AckInteraction(it, InteractionResultSuccess); err != nil {
+		log.Warnln(getLogHeader(), "回应互动事件时出现错误:", err)
+	}
 }
 
 func match(ctx *Ctx, matchers []*Matcher) {
@@ -0,0 +1,183 @@
+package nano
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileInfoCache 缓存上传富媒体获得的 file_info, 以便在有效期内重复发送同一媒体
+type FileInfoCache interface {
+	// Get 获取 key 对应且仍有效的 file_info
+	Get(key string) (fileinfo string, ok bool)
+	// Set 保存 file_info 直至 expire, expire 为零值时长期有效
+	Set(key, fileinfo string, expire time.Time)
+}
+
+// fileInfoEntry 一条缓存的 file_info
+type fileInfoEntry struct {
+	FileInfo string    `json:"file_info"`
+	Expire   time.Time `json:"expire"`
+}
+
+// valid 在 now 时是否有效
+func (e *fileInfoEntry) valid(now time.Time) bool {
+	return e.FileInfo != "" && (e.Expire.IsZero() || now.Before(e.Expire))
+}
+
+// defaultFileInfoCacheSize MemoryFileInfoCache.Size 的默认值
+const defaultFileInfoCacheSize = 1024
+
+// memoryFileInfo MemoryFileInfoCache 中的一项
+type memoryFileInfo struct {
+	key string
+	fileInfoEntry
+}
+
+// MemoryFileInfoCache 内存中的 FileInfoCache, 零值可用, 未设置 Bot.FileInfoCache 时使用
+//
+// 项数达到 Size 时淘汰最早写入的项
+type MemoryFileInfoCache struct {
+	Size int // Size 最多缓存的项数, 不大于 0 时为 1024
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   list.List // order 按写入顺序排列的 *memoryFileInfo, 最早的在前
+}
+
+// Get 实现 FileInfoCache
+func (c *MemoryFileInfoCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*memoryFileInfo)
+	if !e.valid(time.Now()) {
+		c.remove(el)
+		return "", false
+	}
+	return e.FileInfo, true
+}
+
+// Set 实现 FileInfoCache
+func (c *MemoryFileInfoCache) Set(key, fileinfo string, expire time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element, 64)
+	}
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	size := c.Size
+	if size <= 0 {
+		size = defaultFileInfoCacheSize
+	}
+	for len(c.entries) >= size {
+		c.remove(c.order.Front())
+	}
+	c.entries[key] = c.order.PushBack(&memoryFileInfo{
+		key: key, fileInfoEntry: fileInfoEntry{FileInfo: fileinfo, Expire: expire},
+	})
+}
+
+// remove 移除 el, 调用者需持有 mu
+func (c *MemoryFileInfoCache) remove(el *list.Element) {
+	delete(c.entries, c.order.Remove(el).(*memoryFileInfo).key)
+}
+
+// DiskFileInfoCache 以目录保存的 FileInfoCache, 重启后仍可复用, 如 engine.DataFolder()+"fileinfo"
+type DiskFileInfoCache string
+
+// path 缓存 key 的文件路径
+func (c DiskFileInfoCache) path(key string) string {
+	return filepath.Join(string(c), key+".json")
+}
+
+// Get 实现 FileInfoCache
+func (c DiskFileInfoCache) Get(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	var e fileInfoEntry
+	if json.Unmarshal(data, &e) != nil || !e.valid(time.Now()) {
+		_ = os.Remove(c.path(key))
+		return "", false
+	}
+	return e.FileInfo, true
+}
+
+// Set 实现 FileInfoCache
+func (c DiskFileInfoCache) Set(key, fileinfo string, expire time.Time) {
+	data, err := json.Marshal(&fileInfoEntry{FileInfo: fileinfo, Expire: expire})
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(string(c), 0755) != nil {
+		return
+	}
+	tmp := c.path(key) + ".tmp"
+	if os.WriteFile(tmp, data, 0644) == nil {
+		_ = os.Rename(tmp, c.path(key))
+	}
+}
+
+// fileinfocache 实际使用的 FileInfoCache, 自动分片时默认由所有分片共享
+func (bot *Bot) fileinfocache() FileInfoCache {
+	if bot.FileInfoCache != nil {
+		return bot.FileInfoCache
+	}
+	return &bot.tokenholder().ficache
+}
+
+// fileinfokey 由会话 scene, 媒体类型与媒体内容 content 生成缓存键
+func fileinfokey(scene string, tp FileType, content []byte) string {
+	h := sha256.New()
+	h.Write(StringToBytes(scene))
+	h.Write([]byte{0, byte(tp), 0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizemediaurl 统一链接的写法以便缓存
+func normalizemediaurl(u string) string {
+	x, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	x.Scheme = strings.ToLower(x.Scheme)
+	x.Host = strings.ToLower(x.Host)
+	x.Fragment = ""
+	return x.String()
+}
+
+// fileinfoexpire 由 ttl 秒计算缓存的过期时间, 预留 1/10 的余量, ttl 为 0 时长期有效
+func fileinfoexpire(ttl int) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	d := time.Duration(ttl) * time.Second
+	return time.Now().Add(d - d/10)
+}
+
+// qqscene 当前 QQ 会话, 用于区分缓存
+func (ctx *Ctx) qqscene() string {
+	switch {
+	case OnlyQQGroup(ctx):
+		return "group:" + ctx.Message.ChannelID
+	case OnlyQQPrivate(ctx):
+		return "user:" + ctx.Message.Author.ID
+	}
+	return "unknown:" + strconv.Itoa(int(ctx.Seq))
+}
@@ -0,0 +1,68 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileInfoCache(t *testing.T) {
+	var uploads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files") {
+			atomic.AddInt32(&uploads, 1)
+			_, _ = w.Write([]byte(`{"file_info":"info","ttl":3600}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	group := &Ctx{Event: Event{Type: "GroupAtMessageCreate"}, IsQQ: true, Message: &Message{ID: "cache-trigger", ChannelID: "G"}, caller: bot}
+	other := &Ctx{Event: Event{Type: "GroupAtMessageCreate"}, IsQQ: true, Message: &Message{ID: "cache-trigger2", ChannelID: "H"}, caller: bot}
+
+	for i := 0; i < 2; i++ {
+		_, err := group.SendImageBytes([]byte("png"), false)
+		assert.NoError(t, err)
+		_, err = group.SendImage("HTTPS://Example.com/a.png#x", false)
+		assert.NoError(t, err)
+	}
+	_, err := group.SendImage("https://example.com/a.png", false)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&uploads))
+	_, err = other.SendImageBytes([]byte("png"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&uploads))
+
+	mem := &MemoryFileInfoCache{Size: 2}
+	mem.Set("a", "1", time.Time{})
+	mem.Set("b", "2", time.Time{})
+	mem.Set("a", "3", time.Time{}) // 重新写入的项视为最新
+	mem.Set("c", "4", time.Time{})
+	_, ok := mem.Get("b")
+	assert.False(t, ok)
+	info, ok := mem.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "3", info)
+	mem.Set("d", "5", time.Now().Add(-time.Second))
+	_, ok = mem.Get("d")
+	assert.False(t, ok)
+	assert.Len(t, mem.entries, 1)
+
+	disk := DiskFileInfoCache(t.TempDir())
+	disk.Set("a", "info", time.Time{})
+	disk.Set("b", "old", time.Now().Add(-time.Second))
+	info, ok = disk.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "info", info)
+	_, ok = disk.Get("b")
+	assert.False(t, ok)
+	_, ok = disk.Get("c")
+	assert.False(t, ok)
+}
@@ -81,7 +81,9 @@ type Handler struct {
 	OnC2cMsgReject         func(s uint32, bot *Bot, d *QQRobotStatus)
 	OnC2cMsgReceive        func(s uint32, bot *Bot, d *QQRobotStatus)
 
-	// INTERACTION (1 << 26) 事件结构不明
+	// INTERACTION (1 << 26) 互动事件, 处理函数返回后未经 Bot.AckInteraction 回应的互动将以成功回应
+
+	OnInteractionCreate func(s uint32, bot *Bot, d *Interaction)
 
 	// MESSAGE_AUDIT (1 << 27)
 
@@ -107,4 +109,11 @@ type Handler struct {
 
 	OnAtMessageCreate     func(s uint32, bot *Bot, d *Message)
 	OnPublicMessageDelete func(s uint32, bot *Bot, d *MessageDelete)
+	// 生命周期事件, 由 bot 自身产生
+
+	OnReady          func(s uint32, bot *Bot, d *LifecycleEvent)
+	OnResumed        func(s uint32, bot *Bot, d *LifecycleEvent)
+	OnDisconnected   func(s uint32, bot *Bot, d *LifecycleEvent)
+	OnReconnecting   func(s uint32, bot *Bot, d *LifecycleEvent)
+	OnTokenRefreshed func(s uint32, bot *Bot, d *LifecycleEvent)
 }
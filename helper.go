@@ -104,7 +104,7 @@ func resolveURI(addr string) (network, address string) {
 			uri.Scheme = scheme // remove `+unix`/`+tcp4`
 			if ext == "unix" {
 				uri.Host, uri.Path, _ = strings.Cut(uri.Path, ":")
-				uri.Host = base64.StdEncoding.EncodeToString(StringToBytes(uri.Host)) // special handle for unix
+				uri.Host = base64.RawURLEncoding.EncodeToString(StringToBytes(uri.Host)) // special handle for unix
 			}
 			address = uri.String()
 		}
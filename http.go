@@ -2,24 +2,21 @@ package nano
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"net/url"
-	"os"
 	"reflect"
 	"strings"
-	_ "unsafe"
-
-	base14 "github.com/fumiama/go-base16384"
+	"sync"
 )
 
 // HTTPRequsetConstructer ...
-type HTTPRequsetConstructer func(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error)
+type HTTPRequsetConstructer func(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error)
 
 func newHTTPEndpointRequestWithAuth(method, contenttype, ep string, auth, appid string, body io.Reader) (req *http.Request, err error) {
 	req, err = http.NewRequest(method, ep, body)
@@ -40,28 +37,28 @@ func newHTTPEndpointRequestWithAuth(method, contenttype, ep string, auth, appid
 }
 
 // NewHTTPEndpointGetRequestWithAuth 新建带鉴权头的 HTTP GET 请求
-func NewHTTPEndpointGetRequestWithAuth(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
-	return newHTTPEndpointRequestWithAuth("GET", contenttype, OpenAPI+ep, auth, appid, body)
+func NewHTTPEndpointGetRequestWithAuth(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
+	return newHTTPEndpointRequestWithAuth("GET", contenttype, base+ep, auth, appid, body)
 }
 
 // NewHTTPEndpointPutRequestWithAuth 新建带鉴权头的 HTTP PUT 请求
-func NewHTTPEndpointPutRequestWithAuth(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
-	return newHTTPEndpointRequestWithAuth("PUT", contenttype, OpenAPI+ep, auth, appid, body)
+func NewHTTPEndpointPutRequestWithAuth(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
+	return newHTTPEndpointRequestWithAuth("PUT", contenttype, base+ep, auth, appid, body)
 }
 
 // NewHTTPEndpointDeleteRequestWithAuth 新建带鉴权头的 HTTP DELETE 请求
-func NewHTTPEndpointDeleteRequestWithAuth(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
-	return newHTTPEndpointRequestWithAuth("DELETE", contenttype, OpenAPI+ep, auth, appid, body)
+func NewHTTPEndpointDeleteRequestWithAuth(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
+	return newHTTPEndpointRequestWithAuth("DELETE", contenttype, base+ep, auth, appid, body)
 }
 
 // NewHTTPEndpointPostRequestWithAuth 新建带鉴权头的 HTTP POST 请求
-func NewHTTPEndpointPostRequestWithAuth(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
-	return newHTTPEndpointRequestWithAuth("POST", contenttype, OpenAPI+ep, auth, appid, body)
+func NewHTTPEndpointPostRequestWithAuth(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
+	return newHTTPEndpointRequestWithAuth("POST", contenttype, base+ep, auth, appid, body)
 }
 
 // NewHTTPEndpointPatchRequestWithAuth 新建带鉴权头的 HTTP PATCH 请求
-func NewHTTPEndpointPatchRequestWithAuth(ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
-	return newHTTPEndpointRequestWithAuth("PATCH", contenttype, OpenAPI+ep, auth, appid, body)
+func NewHTTPEndpointPatchRequestWithAuth(base, ep string, contenttype string, auth, appid string, body io.Reader) (*http.Request, error) {
+	return newHTTPEndpointRequestWithAuth("PATCH", contenttype, base+ep, auth, appid, body)
 }
 
 // WriteHTTPQueryIfNotNil 如果非空则将请求添加到 baseurl 后
@@ -105,18 +102,68 @@ func WriteBodyFromJSON(ptr any) *bytes.Buffer {
 	return buf
 }
 
-//go:linkname escapeQuotes mime/multipart.escapeQuotes
-func escapeQuotes(s string) string
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 
-// WriteBodyByMultipartFormData 使用 multipart/form-data 上传
-func WriteBodyByMultipartFormData(params ...any) (*bytes.Buffer, string, error) {
-	if len(params)%2 != 0 {
-		panic("invalid params to " + getThisFuncName())
+// escapeQuotes 同 mime/multipart 中的 escapeQuotes
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// mediaparam multipart/form-data 参数中以文件形式上传的部分
+func mediaparam(x any) *MediaSource {
+	switch o := x.(type) {
+	case *MediaSource:
+		return o
+	case []byte:
+		src := MediaBytes(o)
+		src.mime = "application/octet-stream"
+		return src
+	case string:
+		if strings.HasPrefix(o, "file:///") || strings.HasPrefix(o, "base64://") || strings.HasPrefix(o, "base16384://") {
+			return MediaOf(o)
+		}
+	}
+	return nil
+}
+
+// writemediapart 以文件形式写入 src, 超过 MaxUploadSize 时返回 ErrMediaTooLarge
+func writemediapart(w *multipart.Writer, fieldname string, src *MediaSource) error {
+	filename := src.Name()
+	if filename == "" {
+		filename = fieldname
+	}
+	contenttype, err := src.MIME()
+	if err != nil {
+		return err
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(fieldname), escapeQuotes(filename)))
+	h.Set("Content-Type", contenttype)
+	r, err := w.CreatePart(h)
+	if err != nil {
+		return err
 	}
+	f, err := src.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if MaxUploadSize <= 0 {
+		_, err = io.Copy(r, f)
+		return err
+	}
+	n, err := io.Copy(r, io.LimitReader(f, MaxUploadSize+1))
+	if err == nil && n > MaxUploadSize {
+		err = fmt.Errorf("%w: %v exceeds %d bytes", ErrMediaTooLarge, src, MaxUploadSize)
+	}
+	return err
+}
+
+// writemultipart 将 params 写入 w, 格式同 WriteBodyByMultipartFormData
+func writemultipart(w *multipart.Writer, params []any) error {
 	fieldname := ""
-	buf := bytes.NewBuffer(make([]byte, 0, 65536))
-	w := multipart.NewWriter(buf)
-	defer w.Close()
 	for i, x := range params {
 		if i%2 == 0 { // 参数
 			fieldname = x.(string)
@@ -126,113 +173,139 @@ func WriteBodyByMultipartFormData(params ...any) (*bytes.Buffer, string, error)
 		if rx.IsZero() {
 			continue
 		}
-		h := make(textproto.MIMEHeader)
-		if rx.Kind() == reflect.Pointer && rx.Elem().Kind() == reflect.Struct { // 使用 json 编码
-			h.Set("Content-Disposition",
-				fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(fieldname)))
-			h.Set("Content-Type", "application/json")
-			r, err := w.CreatePart(h)
-			if err != nil {
-				return nil, "", err
-			}
-			err = json.NewEncoder(r).Encode(x)
-			if err != nil {
-				return nil, "", err
+		if src := mediaparam(x); src != nil {
+			if err := writemediapart(w, fieldname, src); err != nil {
+				return err
 			}
 			continue
 		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition",
+			fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(fieldname)))
+		h.Set("Content-Type", "application/json")
+		r, err := w.CreatePart(h)
+		if err != nil {
+			return err
+		}
 		switch o := x.(type) {
 		case string:
-			if strings.HasPrefix(o, "file:///") { // 是文件路径
-				h.Set("Content-Disposition",
-					fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-						escapeQuotes(fieldname), escapeQuotes(fieldname)))
-				h.Set("Content-Type", "application/octet-stream")
-				r, err := w.CreatePart(h)
-				if err != nil {
-					return nil, "", err
-				}
-				f, err := os.Open(o[8:])
-				if err != nil {
-					return nil, "", err
-				}
-				defer f.Close()
-				_, err = io.Copy(r, f)
-				if err != nil {
-					return nil, "", err
-				}
-				continue
-			}
-			if strings.HasPrefix(o, "base64://") { // 是 base64
-				h.Set("Content-Disposition",
-					fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-						escapeQuotes(fieldname), escapeQuotes(fieldname)))
-				h.Set("Content-Type", "application/octet-stream")
-				r, err := w.CreatePart(h)
-				if err != nil {
-					return nil, "", err
-				}
-				_, err = io.Copy(r, base64.NewDecoder(base64.StdEncoding, bytes.NewBufferString(o[9:])))
-				if err != nil {
-					return nil, "", err
-				}
-				continue
-			}
-			if strings.HasPrefix(o, "base16384://") { // 是 base16384
-				h.Set("Content-Disposition",
-					fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-						escapeQuotes(fieldname), escapeQuotes(fieldname)))
-				h.Set("Content-Type", "application/octet-stream")
-				r, err := w.CreatePart(h)
-				if err != nil {
-					return nil, "", err
-				}
-				_, err = io.Copy(r, base14.NewDecoder(bytes.NewBufferString(o[12:])))
-				if err != nil {
-					return nil, "", err
-				}
-				continue
-			}
-			h.Set("Content-Disposition",
-				fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(fieldname)))
-			h.Set("Content-Type", "application/json")
-			r, err := w.CreatePart(h)
-			if err != nil {
-				return nil, "", err
-			}
 			_, err = io.WriteString(r, o)
-			if err != nil {
-				return nil, "", err
-			}
-			continue
-		case []byte:
-			h.Set("Content-Disposition",
-				fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-					escapeQuotes(fieldname), escapeQuotes(fieldname)))
-			h.Set("Content-Type", "application/octet-stream")
-			r, err := w.CreatePart(h)
-			if err != nil {
-				return nil, "", err
-			}
-			_, err = r.Write(o)
-			if err != nil {
-				return nil, "", err
-			}
-			continue
 		default:
-			h.Set("Content-Disposition",
-				fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(fieldname)))
-			h.Set("Content-Type", "application/json")
-			r, err := w.CreatePart(h)
-			if err != nil {
-				return nil, "", err
-			}
-			_, err = io.WriteString(r, fmt.Sprint(o))
-			if err != nil {
-				return nil, "", err
+			if rx.Kind() == reflect.Pointer && rx.Elem().Kind() == reflect.Struct { // 使用 json 编码
+				err = json.NewEncoder(r).Encode(x)
+			} else {
+				_, err = io.WriteString(r, fmt.Sprint(o))
 			}
-			continue
 		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBodyByMultipartFormData 使用 multipart/form-data 上传
+//
+// *MediaSource 与 file:///, base64://, base16384:// 形式的 string 以文件形式上传
+func WriteBodyByMultipartFormData(params ...any) (*bytes.Buffer, string, error) {
+	if len(params)%2 != 0 {
+		panic("invalid params to " + getThisFuncName())
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, 65536))
+	w := multipart.NewWriter(buf)
+	if err := writemultipart(w, params); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
 	}
 	return buf, w.FormDataContentType(), nil
 }
+
+// StreamBodyByMultipartFormData 同 WriteBodyByMultipartFormData, 但请求体在发送时才边读边生成
+//
+// 大小已知的文件在此检查 MaxUploadSize; 所有文件均可重读时, 每次重试都将重新生成请求体
+func StreamBodyByMultipartFormData(params ...any) (io.Reader, string, error) {
+	if len(params)%2 != 0 {
+		panic("invalid params to " + getThisFuncName())
+	}
+	s := &multipartStream{params: params, replayable: true}
+	for i := 1; i < len(params); i += 2 {
+		if reflect.ValueOf(params[i]).IsZero() {
+			continue
+		}
+		src := mediaparam(params[i])
+		if src == nil {
+			continue
+		}
+		if err := src.checksize(); err != nil {
+			return nil, "", err
+		}
+		if !src.replayable() {
+			s.replayable = false
+		}
+	}
+	w := multipart.NewWriter(io.Discard)
+	s.boundary = w.Boundary()
+	return s, w.FormDataContentType(), nil
+}
+
+// multipartStream 按需由 goroutine 经 io.Pipe 生成的 multipart/form-data 请求体
+type multipartStream struct {
+	params     []any
+	boundary   string
+	replayable bool
+
+	mu     sync.Mutex
+	r      io.ReadCloser // r 直接 Read 时使用的请求体
+	closed bool
+}
+
+// open 新建一份独立的请求体, 关闭后结束生成它的 goroutine
+func (s *multipartStream) open() io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w := multipart.NewWriter(pw)
+		_ = w.SetBoundary(s.boundary)
+		err := writemultipart(w, s.params)
+		if err == nil {
+			err = w.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// getbody 用作 http.Request.GetBody, 仅在所有文件均可重读时可用
+func (s *multipartStream) getbody() (io.ReadCloser, error) {
+	if !s.replayable {
+		return nil, errors.New("multipart stream is not replayable")
+	}
+	return s.open(), nil
+}
+
+// Read 首次读取时开始生成请求体, 仅能读取一次
+func (s *multipartStream) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	if s.r == nil {
+		if s.closed {
+			s.mu.Unlock()
+			return 0, io.ErrClosedPipe
+		}
+		s.r = s.open()
+	}
+	r := s.r
+	s.mu.Unlock()
+	return r.Read(p)
+}
+
+// Close 结束 Read 时开始的生成
+func (s *multipartStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.r != nil {
+		return s.r.Close()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package nano
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInteractionButton(t *testing.T) {
+	acks := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		data, _ := io.ReadAll(r.Body)
+		acks <- r.URL.Path + " " + strings.TrimSpace(string(data))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	args := make(chan string, 4)
+	e := newEngine()
+	defer e.Delete()
+	e.OnButton("vote:").Handle(func(ctx *Ctx) {
+		args <- ctx.State["args"].(string)
+		assert.NoError(t, ctx.AckInteraction(InteractionResultDuplicated))
+	})
+
+	dispatch := func(id, data string) {
+		payload := &WebsocketPayload{Op: OpCodeDispatch, T: "INTERACTION_CREATE", S: 1,
+			D: json.RawMessage(`{"id":"` + id + `","type":11,"scene":"group","chat_type":1,"group_openid":"G",` +
+				`"data":{"type":11,"resolved":{"button_id":"1","button_data":"` + data + `"}}}`)}
+		bot.processEvent(payload)
+	}
+	dispatch("i1", "vote:yes")
+	select {
+	case arg := <-args:
+		assert.Equal(t, "yes", arg)
+	case <-time.After(time.Second):
+		t.Fatal("button matcher not triggered")
+	}
+	assert.Equal(t, `/interactions/i1 {"code":3}`, <-acks)
+
+	dispatch("i2", "other")
+	assert.Equal(t, `/interactions/i2 {"code":0}`, <-acks)
+	assert.Len(t, args, 0)
+}
+
+func TestInteractionReply(t *testing.T) {
+	reqs := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		reqs <- r.Method + " " + r.URL.Path + " " + strings.TrimSpace(string(data))
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"r1"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	e := newEngine()
+	defer e.Delete()
+	triggeredMessages.Delete("i3")
+	e.OnButton("hi").Handle(func(ctx *Ctx) {
+		assert.True(t, OnlyQQGroup(ctx))
+		assert.Equal(t, "M", ctx.Message.Author.ID)
+		_, err := ctx.SendPlainMessage(false, "hello")
+		assert.NoError(t, err)
+	})
+	bot.processEvent(&WebsocketPayload{Op: OpCodeDispatch, T: "INTERACTION_CREATE", S: 1,
+		D: json.RawMessage(`{"id":"i3","type":11,"scene":"group","chat_type":1,"group_openid":"G","group_member_openid":"M",` +
+			`"data":{"type":11,"resolved":{"button_id":"1","button_data":"hi"}}}`)})
+	assert.Equal(t, `POST /v2/groups/G/messages {"msg_type":0,"msg_seq":1,"content":"hello","event_id":"i3"}`, <-reqs)
+	assert.Equal(t, `PUT /interactions/i3 {"code":0}`, <-reqs)
+}
+
+func TestInteractionAutoAck(t *testing.T) {
+	acks := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acks <- r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	payload := &WebsocketPayload{Op: OpCodeDispatch, T: "INTERACTION_CREATE", S: 1,
+		D: json.RawMessage(`{"id":"i4","type":11,"chat_type":2,"user_openid":"U","data":{"type":11}}`)}
+
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	bot.processEvent(payload) // 未注册 matcher
+	assert.Equal(t, "/interactions/i4", <-acks)
+
+	handled := make(chan string, 1)
+	hbot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL, Handler: &Handler{
+		OnInteractionCreate: func(s uint32, bot *Bot, d *Interaction) {
+			handled <- d.UserOpenID
+		},
+	}}).Init("", "", [2]byte{0, 1})
+	defer hbot.Close()
+	hbot.processEvent(payload)
+	assert.Equal(t, "U", <-handled)
+	assert.Equal(t, "/interactions/i4", <-acks)
+
+	nbot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL, Handler: &Handler{}}).Init("", "", [2]byte{0, 1})
+	defer nbot.Close()
+	nbot.processEvent(payload) // 未注册处理函数
+	assert.Equal(t, "/interactions/i4", <-acks)
+}
@@ -0,0 +1,187 @@
+package nano
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNilAPIResponse 拦截器未返回错误也未返回 Response
+var ErrNilAPIResponse = errors.New("interceptor returned nil response")
+
+// APIRequest 经过拦截器链的一次 OpenAPI 请求
+type APIRequest struct {
+	*http.Request
+	Route  string // Route 路由模版, ex. POST /channels/{id}/messages
+	Target string // Target 路由中的首个 ID
+	Caller string // Caller 调用的 API 函数名
+}
+
+// Payload 读取请求体而不消耗它, 请求体不可重读时返回 nil
+func (r *APIRequest) Payload() ([]byte, error) {
+	if r.Request.Body == nil || r.Request.Body == http.NoBody || r.Request.GetBody == nil {
+		return nil, nil
+	}
+	body, err := r.Request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// Decode 将 json 请求体解析到 v
+func (r *APIRequest) Decode(v any) error {
+	data, err := r.Payload()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.New("request body is not replayable")
+	}
+	return json.Unmarshal(data, v)
+}
+
+// APIResponse 经过拦截器链的 OpenAPI 响应, Body 已读取到 Data
+type APIResponse struct {
+	*http.Response
+	Data []byte
+}
+
+// RoundTrip 发送 APIRequest 并获得 APIResponse, err 仅表示未能获得响应
+type RoundTrip func(req *APIRequest) (*APIResponse, error)
+
+// Interceptor OpenAPI 请求拦截器, 在 next 前后观察或修改请求与响应, 也可不调用 next 直接返回,
+// 此时须返回错误或带有 Response 的 APIResponse, 否则视为 ErrNilAPIResponse
+type Interceptor func(next RoundTrip) RoundTrip
+
+// roundtrip 实际发送请求
+func (bot *Bot) roundtrip(req *APIRequest) (*APIResponse, error) {
+	resp, err := bot.client.Do(req.Request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &APIResponse{Response: resp, Data: data}, nil
+}
+
+// interceptedroundtrip 按 Bot.Interceptors 的顺序包装 roundtrip, 第一个在最外层
+func (bot *Bot) interceptedroundtrip() RoundTrip {
+	rt := bot.roundtrip
+	for i := len(bot.Interceptors) - 1; i >= 0; i-- {
+		rt = checkresponse(bot.Interceptors[i](rt))
+	}
+	return rt
+}
+
+// checkresponse 保证 rt 返回错误或非 nil 的 Response, 使外层拦截器可直接使用 resp
+func checkresponse(rt RoundTrip) RoundTrip {
+	return func(req *APIRequest) (*APIResponse, error) {
+		resp, err := rt(req)
+		if err == nil && (resp == nil || resp.Response == nil) {
+			return nil, ErrNilAPIResponse
+		}
+		return resp, err
+	}
+}
+
+// LoggingInterceptor 以结构化字段记录每个请求的路由、状态码、耗时与 trace id
+func LoggingInterceptor(next RoundTrip) RoundTrip {
+	return func(req *APIRequest) (*APIResponse, error) {
+		start := time.Now()
+		resp, err := next(req)
+		entry := log.WithFields(log.Fields{
+			"caller":  req.Caller,
+			"route":   req.Route,
+			"target":  req.Target,
+			"latency": time.Since(start),
+		})
+		if err != nil {
+			entry.WithError(err).Warnln(getLogHeader(), "OpenAPI 请求失败")
+			return resp, err
+		}
+		entry = entry.WithFields(log.Fields{
+			"status": resp.StatusCode,
+			"trace":  resp.Header.Get("X-Tps-trace-ID"),
+		})
+		if resp.StatusCode >= http.StatusBadRequest {
+			entry.Warnln(getLogHeader(), "OpenAPI 请求返回错误")
+		} else {
+			entry.Debugln(getLogHeader(), "OpenAPI 请求完成")
+		}
+		return resp, err
+	}
+}
+
+// RouteMetrics 一个路由的请求统计
+type RouteMetrics struct {
+	Route  string
+	Count  int64         // Count 请求数
+	Errors int64         // Errors 未获得响应或状态码不小于 400 的请求数
+	Total  time.Duration // Total 累计耗时
+	Max    time.Duration // Max 最大耗时
+}
+
+// Mean 平均耗时
+func (m RouteMetrics) Mean() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Count)
+}
+
+// APIMetrics 按路由模版统计请求耗时, 零值可用, 将 Intercept 加入 Bot.Interceptors 即可
+type APIMetrics struct {
+	mu     sync.Mutex
+	routes map[string]*RouteMetrics
+}
+
+// Intercept 实现 Interceptor
+func (m *APIMetrics) Intercept(next RoundTrip) RoundTrip {
+	return func(req *APIRequest) (*APIResponse, error) {
+		start := time.Now()
+		resp, err := next(req)
+		d := time.Since(start)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.routes == nil {
+			m.routes = make(map[string]*RouteMetrics, 16)
+		}
+		rm := m.routes[req.Route]
+		if rm == nil {
+			rm = &RouteMetrics{Route: req.Route}
+			m.routes[req.Route] = rm
+		}
+		rm.Count++
+		if err != nil || resp.StatusCode >= http.StatusBadRequest {
+			rm.Errors++
+		}
+		rm.Total += d
+		if d > rm.Max {
+			rm.Max = d
+		}
+		return resp, err
+	}
+}
+
+// Routes 按路由排序的统计快照
+func (m *APIMetrics) Routes() []RouteMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rms := make([]RouteMetrics, 0, len(m.routes))
+	for _, rm := range m.routes {
+		rms = append(rms, *rm)
+	}
+	sort.Slice(rms, func(i, j int) bool { return rms[i].Route < rms[j].Route })
+	return rms
+}
@@ -0,0 +1,96 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "yes", r.Header.Get("X-Audit"))
+		_, _ = w.Write([]byte(`{"id":"1","content":"ok","username":"nano"}`))
+	}))
+	defer srv.Close()
+	var order []string
+	var posted MessagePost
+	errDryRun := errors.New("dry run")
+	metrics := &APIMetrics{}
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		Interceptors: []Interceptor{
+			LoggingInterceptor,
+			metrics.Intercept,
+			func(next RoundTrip) RoundTrip {
+				return func(req *APIRequest) (*APIResponse, error) {
+					order = append(order, req.Route)
+					req.Header.Set("X-Audit", "yes")
+					if req.Method == http.MethodPost {
+						assert.NoError(t, req.Decode(&posted))
+						return nil, errDryRun
+					}
+					return next(req)
+				}
+			},
+		},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	u, err := bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "nano", u.Username)
+	_, err = bot.PostMessageToChannel("123", &MessagePost{Content: "hello"})
+	assert.ErrorIs(t, err, errDryRun)
+	assert.Equal(t, "hello", posted.Content)
+	assert.Equal(t, []string{"GET /users/@me", "POST /channels/{id}/messages"}, order)
+
+	routes := metrics.Routes()
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "GET /users/@me", routes[0].Route)
+	assert.Equal(t, int64(1), routes[0].Count)
+	assert.Equal(t, int64(0), routes[0].Errors)
+	assert.Equal(t, int64(1), routes[1].Errors)
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("short-circuited request reached the server")
+	}))
+	defer srv.Close()
+	metrics := &APIMetrics{}
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		Interceptors: []Interceptor{
+			LoggingInterceptor,
+			metrics.Intercept,
+			func(next RoundTrip) RoundTrip {
+				return func(req *APIRequest) (*APIResponse, error) {
+					switch req.Method {
+					case http.MethodGet:
+						return &APIResponse{
+							Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+							Data:     []byte(`{"id":"1","username":"cached"}`),
+						}, nil
+					case http.MethodPost:
+						return nil, nil
+					}
+					return &APIResponse{}, nil
+				}
+			},
+		},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	u, err := bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", u.Username)
+	_, err = bot.PostMessageToChannel("123", &MessagePost{Content: "hello"})
+	assert.ErrorIs(t, err, ErrNilAPIResponse)
+	err = bot.DeleteMessageInChannel("123", "456", false)
+	assert.ErrorIs(t, err, ErrNilAPIResponse)
+	routes := metrics.Routes()
+	assert.Len(t, routes, 3)
+}
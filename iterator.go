@@ -0,0 +1,152 @@
+package nano
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrIteratorDone 迭代器已无更多数据
+	ErrIteratorDone = errors.New("no more items in iterator")
+)
+
+// pageFetcher 以 cursor 获取一页, 返回下一页的 cursor 以及是否已是最后一页
+type pageFetcher[T any] func(bot *Bot, cursor string) (items []T, next string, end bool, err error)
+
+// pageIterator 按 cursor 翻页, 去除 key 非空的重复项并检测结束
+type pageIterator[T any] struct {
+	bot    *Bot
+	fetch  pageFetcher[T]
+	key    func(*T) string
+	cursor string
+	seen   map[string]struct{}
+	done   bool
+}
+
+func newPageIterator[T any](bot *Bot, cursor string, fetch pageFetcher[T], key func(*T) string) pageIterator[T] {
+	return pageIterator[T]{bot: bot, fetch: fetch, key: key, cursor: cursor, seen: make(map[string]struct{}, 64)}
+}
+
+// Next 获取下一页中未出现过的项, 无更多数据时返回 ErrIteratorDone
+//
+// 请求经过 bot 的限流器, ctx 结束时取消请求
+func (it *pageIterator[T]) Next(ctx context.Context) ([]T, error) {
+	for !it.done {
+		items, next, end, err := it.fetch(it.bot.WithContext(ctx), it.cursor)
+		if err != nil {
+			return nil, err
+		}
+		it.done = end || next == "" || next == it.cursor
+		it.cursor = next
+		page := items[:0]
+		for i := range items {
+			if k := it.key(&items[i]); k != "" { // 无法取得键的项不去重
+				if _, ok := it.seen[k]; ok {
+					continue
+				}
+				it.seen[k] = struct{}{}
+			}
+			page = append(page, items[i])
+		}
+		if len(page) > 0 {
+			return page, nil
+		}
+	}
+	return nil, ErrIteratorDone
+}
+
+// All 获取剩余的全部项
+func (it *pageIterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for {
+		page, err := it.Next(ctx)
+		if err == ErrIteratorDone {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, page...)
+	}
+}
+
+// memberkey 成员以用户 ID 去重
+func memberkey(m *Member) string {
+	if m.User == nil {
+		return ""
+	}
+	return m.User.ID
+}
+
+// MemberIterator 频道成员迭代器
+type MemberIterator struct {
+	pageIterator[Member]
+}
+
+// IterGuildMembersIn 迭代 guild_id 指定的频道中的所有成员, limit 为每页数量 1-400
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/member/get_members.html
+func (bot *Bot) IterGuildMembersIn(id string, limit uint32) *MemberIterator {
+	return &MemberIterator{newPageIterator(bot, "0", func(bot *Bot, cursor string) ([]Member, string, bool, error) {
+		members, err := bot.GetGuildMembersIn(id, cursor, limit)
+		if err != nil || len(members) == 0 {
+			return nil, "", true, err
+		}
+		return members, memberkey(&members[len(members)-1]), false, nil // 不足一页不代表结束, 以空页为准
+	}, memberkey)}
+}
+
+// RoleMemberIterator 身份组成员迭代器
+type RoleMemberIterator struct {
+	pageIterator[Member]
+}
+
+// IterRoleMembersOf 迭代 guild_id 频道中 role_id 身份组下的所有成员, limit 为每页数量 1-400
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/member/get_role_members.html
+func (bot *Bot) IterRoleMembersOf(guildid, roleid string, limit uint32) *RoleMemberIterator {
+	return &RoleMemberIterator{newPageIterator(bot, "0", func(bot *Bot, cursor string) ([]Member, string, bool, error) {
+		rm, err := bot.GetRoleMembersOf(guildid, roleid, cursor, limit)
+		if err != nil {
+			return nil, "", true, err
+		}
+		return rm.Data, rm.Next, len(rm.Data) == 0, nil
+	}, memberkey)}
+}
+
+// GuildIterator 机器人加入的频道迭代器
+type GuildIterator struct {
+	pageIterator[Guild]
+}
+
+// IterMyGuilds 迭代当前用户（机器人）加入的所有频道, limit 为每页数量 1-100
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/user/guilds.html
+func (bot *Bot) IterMyGuilds(limit int) *GuildIterator {
+	return &GuildIterator{newPageIterator(bot, "", func(bot *Bot, cursor string) ([]Guild, string, bool, error) {
+		guilds, err := bot.GetMyGuilds("", cursor, limit)
+		if err != nil || len(guilds) == 0 {
+			return nil, "", true, err
+		}
+		return guilds, guilds[len(guilds)-1].ID, len(guilds) < limit, nil
+	}, func(g *Guild) string { return g.ID })}
+}
+
+// ReactionUserIterator 表情表态用户迭代器
+type ReactionUserIterator struct {
+	pageIterator[User]
+}
+
+// IterMessageReactionUsers 迭代对消息 message_id 指定表情表态的所有用户, limit 为每页数量 1-50
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/reaction/get_reaction_users.html
+func (bot *Bot) IterMessageReactionUsers(channelid, messageid string, emoji Emoji, limit int) *ReactionUserIterator {
+	return &ReactionUserIterator{newPageIterator(bot, "", func(bot *Bot, cursor string) ([]User, string, bool, error) {
+		mru, err := bot.GetMessageReactionUsers(channelid, messageid, emoji, cursor, limit)
+		if err != nil {
+			return nil, "", true, err
+		}
+		return mru.Users, mru.Cookie, mru.IsEnd, nil
+	}, func(u *User) string { return u.ID })}
+}
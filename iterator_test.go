@@ -0,0 +1,73 @@
+package nano
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/guilds/1/members":
+			switch q.Get("after") {
+			case "0":
+				_, _ = w.Write([]byte(`[{"user":{"id":"a"}},{"user":{"id":"b"}}]`))
+			case "b": // 与上页重叠
+				_, _ = w.Write([]byte(`[{"user":{"id":"b"}},{"user":{"id":"c"}}]`))
+			case "c": // 不足一页但并非最后一页
+				_, _ = w.Write([]byte(`[{"user":{"id":"d"}}]`))
+			case "d":
+				_, _ = w.Write([]byte(`[{"user":{"id":"e"}}]`))
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		case "/guilds/1/roles/2/members":
+			if q.Get("start_index") == "0" {
+				_, _ = w.Write([]byte(`{"data":[{"nick":"a"},{"nick":"b"}],"next":"1"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[],"next":"1"}`))
+		case "/channels/1/messages/2/reactions/1/4":
+			if q.Get("cookie") == "" {
+				_, _ = w.Write([]byte(`{"users":[{"id":"x"}],"cookie":"next","is_end":false}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"users":[{"id":"y"}],"cookie":"","is_end":true}`))
+		}
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	it := bot.IterGuildMembersIn("1", 2)
+	page, err := it.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	members, err := it.All(context.Background())
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, m := range members {
+		ids = append(ids, m.User.ID)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, ids)
+	_, err = it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrIteratorDone)
+
+	members, err = bot.IterRoleMembersOf("1", "2", 2).All(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, members, 2) // 无 user 的成员不去重
+
+	users, err := bot.IterMessageReactionUsers("1", "2", Emoji{ID: "4", Type: 1}, 50).All(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = bot.IterMyGuilds(100).Next(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,196 @@
+package nano
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxKeyboardRows 消息按钮的最大行数
+	MaxKeyboardRows = 5
+	// MaxKeyboardRowButtons 每行消息按钮的最大个数
+	MaxKeyboardRowButtons = 5
+)
+
+// ErrInvalidKeyboard KeyboardBuilder 构造的消息按钮不符合平台限制
+var ErrInvalidKeyboard = errors.New("invalid keyboard")
+
+// ButtonOption 消息按钮的配置项
+type ButtonOption func(*InlineKeyboardButton)
+
+// WithButtonID 指定按钮 ID, 默认按顺序编号
+func WithButtonID(id string) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.ID = id
+	}
+}
+
+// WithVisitedLabel 指定点击后的文字, 默认同 label
+func WithVisitedLabel(label string) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.RenderData.VisitedLabel = label
+	}
+}
+
+// WithButtonStyle 指定按钮样式, 默认灰色
+func WithButtonStyle(style InlineKeyboardButtonRenderDataStyle) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.RenderData.Style = style
+	}
+}
+
+// WithActionType 指定按钮操作, 默认为回调, 可由 Engine.OnButton 处理
+func WithActionType(tp InlineKeyboardButtonActionType) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.Type = tp
+	}
+}
+
+// WithAdminOnly 仅管理者可点击
+func WithAdminOnly() ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.Permission = InlineKeyboardButtonActionPermission{Type: InlineKeyboardButtonActionPermissionTypeAdmin}
+	}
+}
+
+// WithSpecifyUsers 仅指定的用户可点击
+func WithSpecifyUsers(ids ...string) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.Permission = InlineKeyboardButtonActionPermission{
+			Type:           InlineKeyboardButtonActionPermissionTypeShimeiUser,
+			SpecifyUserIDs: ids,
+		}
+	}
+}
+
+// WithSpecifyRoles 仅指定身份组的成员可点击
+func WithSpecifyRoles(ids ...string) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.Permission = InlineKeyboardButtonActionPermission{
+			Type:           InlineKeyboardButtonActionPermissionTypeShimeiRole,
+			SpecifyRoleIDs: ids,
+		}
+	}
+}
+
+// WithClickLimit 指定可点击的次数
+func WithClickLimit(n int) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.ClickLimit = n
+	}
+}
+
+// WithUnsupportTips 指定客户端不支持此操作时的提示
+func WithUnsupportTips(tips string) ButtonOption {
+	return func(b *InlineKeyboardButton) {
+		b.Action.UnsupportTips = tips
+	}
+}
+
+// KeyboardBuilder 链式构造消息按钮, ex. NewKeyboard().Row().Button("是", "vote:yes").Button("否", "vote:no")
+type KeyboardBuilder struct {
+	id   string
+	rows []InlineKeyboardRow
+}
+
+// NewKeyboard 新建自定义消息按钮
+func NewKeyboard() *KeyboardBuilder {
+	return &KeyboardBuilder{}
+}
+
+// Row 开始新的一行
+func (kb *KeyboardBuilder) Row() *KeyboardBuilder {
+	kb.rows = append(kb.rows, InlineKeyboardRow{})
+	return kb
+}
+
+// Button 在当前行末尾添加按钮, label 为显示的文字, data 为回调数据或链接等
+func (kb *KeyboardBuilder) Button(label, data string, opts ...ButtonOption) *KeyboardBuilder {
+	if len(kb.rows) == 0 {
+		kb.Row()
+	}
+	b := InlineKeyboardButton{
+		RenderData: InlineKeyboardButtonRenderData{Label: label, VisitedLabel: label},
+		Action: InlineKeyboardButtonAction{
+			Type:       InlineKeyboardButtonActionTypeCallback,
+			Permission: InlineKeyboardButtonActionPermission{Type: InlineKeyboardButtonActionPermissionTypeAll},
+			Data:       data,
+		},
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	row := &kb.rows[len(kb.rows)-1]
+	row.Buttons = append(row.Buttons, b)
+	return kb
+}
+
+// Build 校验并生成 MessageKeyboard, 未指定 ID 的按钮按顺序编号
+func (kb *KeyboardBuilder) Build() (*MessageKeyboard, error) {
+	if kb.id != "" {
+		return &MessageKeyboard{ID: kb.id}, nil
+	}
+	if len(kb.rows) == 0 {
+		return nil, fmt.Errorf("%w: no button", ErrInvalidKeyboard)
+	}
+	if len(kb.rows) > MaxKeyboardRows {
+		return nil, fmt.Errorf("%w: %d rows, max is %d", ErrInvalidKeyboard, len(kb.rows), MaxKeyboardRows)
+	}
+	ids := make(map[string]struct{}, 16)
+	n := 0
+	rows := make([]InlineKeyboardRow, len(kb.rows))
+	for i, row := range kb.rows {
+		if len(row.Buttons) == 0 {
+			return nil, fmt.Errorf("%w: row %d is empty", ErrInvalidKeyboard, i+1)
+		}
+		if len(row.Buttons) > MaxKeyboardRowButtons {
+			return nil, fmt.Errorf("%w: row %d has %d buttons, max is %d", ErrInvalidKeyboard, i+1, len(row.Buttons), MaxKeyboardRowButtons)
+		}
+		rows[i].Buttons = make([]InlineKeyboardButton, len(row.Buttons))
+		for j, b := range row.Buttons {
+			n++
+			if b.ID == "" {
+				b.ID = strconv.Itoa(n)
+			}
+			if err := checkbutton(&b); err != nil {
+				return nil, fmt.Errorf("%w: button %q in row %d: %v", ErrInvalidKeyboard, b.RenderData.Label, i+1, err)
+			}
+			if _, ok := ids[b.ID]; ok {
+				return nil, fmt.Errorf("%w: duplicate button id %q", ErrInvalidKeyboard, b.ID)
+			}
+			ids[b.ID] = struct{}{}
+			rows[i].Buttons[j] = b
+		}
+	}
+	return &MessageKeyboard{Content: &InlineKeyboard{Rows: rows}}, nil
+}
+
+// checkbutton 校验按钮的文字、数据与权限
+func checkbutton(b *InlineKeyboardButton) error {
+	switch {
+	case b.RenderData.Label == "":
+		return errors.New("empty label")
+	case b.Action.Data == "":
+		return errors.New("empty data")
+	case b.Action.Type == InlineKeyboardButtonActionTypeHTTP &&
+		!strings.HasPrefix(b.Action.Data, "http://") && !strings.HasPrefix(b.Action.Data, "https://"):
+		return errors.New("data of http action is not an url")
+	}
+	p := &b.Action.Permission
+	switch p.Type {
+	case InlineKeyboardButtonActionPermissionTypeShimeiUser:
+		if len(p.SpecifyUserIDs) == 0 {
+			return errors.New("no user is allowed to click")
+		}
+	case InlineKeyboardButtonActionPermissionTypeShimeiRole:
+		if len(p.SpecifyRoleIDs) == 0 {
+			return errors.New("no role is allowed to click")
+		}
+	case InlineKeyboardButtonActionPermissionTypeAdmin, InlineKeyboardButtonActionPermissionTypeAll:
+	default:
+		return errors.New("unknown permission type " + strconv.Itoa(int(p.Type)))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package nano
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyboardBuilder(t *testing.T) {
+	kb, err := NewKeyboard().
+		Row().Button("是", "vote:yes", WithButtonStyle(InlineKeyboardButtonRenderDataStyleBlue)).Button("否", "vote:no").
+		Row().Button("主页", "https://example.com", WithActionType(InlineKeyboardButtonActionTypeHTTP), WithAdminOnly()).
+		Build()
+	assert.NoError(t, err)
+	rows := kb.Content.Rows
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "1", rows[0].Buttons[0].ID)
+	assert.Equal(t, "3", rows[1].Buttons[0].ID)
+	assert.Equal(t, "否", rows[0].Buttons[1].RenderData.VisitedLabel)
+	assert.Equal(t, InlineKeyboardButtonActionTypeCallback, rows[0].Buttons[1].Action.Type)
+	assert.Equal(t, InlineKeyboardButtonActionPermissionTypeAll, rows[0].Buttons[1].Action.Permission.Type)
+	assert.Equal(t, InlineKeyboardButtonActionPermissionTypeAdmin, rows[1].Buttons[0].Action.Permission.Type)
+
+	full := NewKeyboard()
+	for i := 0; i < MaxKeyboardRowButtons+1; i++ {
+		full.Button("x", "x")
+	}
+	for _, kb := range []*KeyboardBuilder{
+		NewKeyboard(),
+		NewKeyboard().Row().Row().Button("a", "a"),
+		NewKeyboard().Row().Row().Row().Row().Row().Row().Button("a", "a"),
+		full,
+		NewKeyboard().Button("", "a"),
+		NewKeyboard().Button("a", "not url", WithActionType(InlineKeyboardButtonActionTypeHTTP)),
+		NewKeyboard().Button("a", "a", WithSpecifyUsers()),
+		NewKeyboard().Button("a", "a", WithButtonID("x")).Button("b", "b", WithButtonID("x")),
+	} {
+		_, err := kb.Build()
+		assert.ErrorIs(t, err, ErrInvalidKeyboard)
+	}
+}
+
+func TestSendMarkdown(t *testing.T) {
+	var posts []MessagePost
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var post MessagePost
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&post))
+		posts = append(posts, post)
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "1024", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	triggeredMessages.Delete("markdown-trigger")
+	ctx := &Ctx{Event: Event{Type: "GroupAtMessageCreate"}, IsQQ: true, Message: &Message{ID: "markdown-trigger", ChannelID: "G"}, caller: bot}
+
+	_, err := ctx.SendChain(Text("hello"), Keyboard(NewKeyboard().Button("a", "a")))
+	assert.ErrorIs(t, err, ErrKeyboardWithoutMarkdown)
+	_, err = ctx.SendChain(Text("hello"), Markdown("# hi"), Keyboard(NewKeyboard().Button("a", "a", WithSpecifyRoles())))
+	assert.ErrorIs(t, err, ErrInvalidKeyboard)
+	assert.Empty(t, posts)
+
+	_, err = ctx.SendChain(Text("hello"), Markdown("# hi"), Keyboard(NewKeyboard().Row().Button("a", "a")))
+	assert.NoError(t, err)
+	_, err = ctx.SendChain(MarkdownTemplate("tpl", map[string][]string{"b": {"2"}, "a": {"1"}}), KeyboardTemplate("kb"))
+	assert.NoError(t, err)
+
+	assert.Len(t, posts, 3)
+	assert.Equal(t, MessageTypeText, posts[0].Type)
+	assert.Equal(t, "hello", posts[0].Content)
+	assert.Equal(t, MessageTypeMarkdown, posts[1].Type)
+	assert.Equal(t, "# hi", posts[1].Markdown.Content)
+	assert.Equal(t, 1024, posts[1].KeyBoard.Content.BotAppID)
+	assert.Equal(t, "a", posts[1].KeyBoard.Content.Rows[0].Buttons[0].Action.Data)
+	assert.Equal(t, 2, posts[1].Seq)
+	assert.Equal(t, MessageTypeMarkdown, posts[2].Type)
+	assert.Equal(t, []MessageMarkdownParams{{Key: "a", Values: []string{"1"}}, {Key: "b", Values: []string{"2"}}}, posts[2].Markdown.Params)
+	assert.Equal(t, &MessageKeyboard{ID: "kb"}, posts[2].KeyBoard)
+}
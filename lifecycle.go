@@ -0,0 +1,40 @@
+package nano
+
+import (
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// 由 bot 自身产生的生命周期事件类型, 与网关事件一样经 processEvent 分发
+const (
+	EventTypeReady          = "READY"           // EventTypeReady Identify 成功或以回调方式初始化完成
+	EventTypeResumed        = "RESUMED"         // EventTypeResumed 网关连接恢复完成
+	EventTypeDisconnected   = "DISCONNECTED"    // EventTypeDisconnected 网关连接断开
+	EventTypeReconnecting   = "RECONNECTING"    // EventTypeReconnecting 即将尝试恢复或重连
+	EventTypeTokenRefreshed = "TOKEN_REFRESHED" // EventTypeTokenRefreshed 刷新 Token 成功
+)
+
+// LifecycleEvent 生命周期事件
+type LifecycleEvent struct {
+	Shard   [2]byte     `json:"shard"`             // Shard 分片序号与分片总数
+	Ready   *EventReady `json:"ready,omitempty"`   // Ready 连接的基本信息
+	Error   string      `json:"error,omitempty"`   // Error 断开或重连的原因
+	Attempt int         `json:"attempt,omitempty"` // Attempt 第几次尝试恢复, 0 为重新 Identify
+}
+
+// lifecycle 分发生命周期事件 tp
+func (bot *Bot) lifecycle(tp string, ev *LifecycleEvent) {
+	ev.Shard = bot.shard
+	if ev.Ready == nil {
+		r := bot.ready
+		ev.Ready = &r
+	}
+	payload := WebsocketPayload{Op: OpCodeDispatch, T: tp, S: atomic.LoadUint32(&bot.seq)}
+	err := payload.WrapData(ev)
+	if err != nil {
+		log.Warnln(getLogHeader(), "包装", tp, "事件时出现错误:", err)
+		return
+	}
+	bot.processEvent(&payload)
+}
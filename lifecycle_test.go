@@ -0,0 +1,43 @@
+package nano
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotLifecycle(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool {
+		return n > 1 || i < 1
+	})
+	defer gw.Close()
+	evs := make(chan string, 16)
+	record := func(tp string) func(s uint32, bot *Bot, d *LifecycleEvent) {
+		return func(s uint32, bot *Bot, d *LifecycleEvent) {
+			assert.Equal(t, [2]byte{0, 1}, d.Shard)
+			assert.Equal(t, "fake", d.Ready.SessionID)
+			evs <- tp
+		}
+	}
+	bot := (&Bot{
+		AppID: "test", Token: "test",
+		ReconnectPolicy: &ExponentialBackoff{Base: 10 * time.Millisecond},
+		Handler: &Handler{
+			OnReady:        record(EventTypeReady),
+			OnDisconnected: record(EventTypeDisconnected),
+			OnReconnecting: record(EventTypeReconnecting),
+			OnResumed:      record(EventTypeResumed),
+		},
+	}).Init("", gw.url(), [2]byte{0, 1}).Connect()
+	defer bot.Close()
+	go bot.Listen()
+	for _, tp := range []string{EventTypeReady, EventTypeDisconnected, EventTypeReconnecting, EventTypeResumed} {
+		select {
+		case ev := <-evs:
+			assert.Equal(t, tp, ev)
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing lifecycle event", tp)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"unicode"
 
@@ -24,6 +25,7 @@ type Manager ctrl.Manager[*Ctx]
 
 var (
 	enmap     = make(map[string]*Engine)
+	enmu      sync.RWMutex              // enmu 保护 enmap
 	priomap   = make(map[int]string)    // priomap is map[prio]service
 	foldermap = make(map[string]string) // foldermap is map[folder]service
 	prio      uint64
@@ -71,13 +73,17 @@ func Register(service string, o *ctrl.Options[*Ctx]) *Engine {
 		}
 	}
 	logrus.Debugln("[control]插件", service, "已设置数据目录", e.datafolder)
+	enmu.Lock()
 	enmap[service] = e
+	enmu.Unlock()
 	return e
 }
 
 // Delete 删除插件控制器, 不会删除数据
 func Delete(service string) {
+	enmu.RLock()
 	engine, ok := enmap[service]
+	enmu.RUnlock()
 	if ok {
 		engine.Delete()
 		m.RLock()
@@ -107,6 +113,8 @@ func cpmp2lstbyprio() []*ctrl.Control[*Ctx] {
 	for _, v := range m.M {
 		ret = append(ret, v)
 	}
+	enmu.RLock()
+	defer enmu.RUnlock()
 	sort.SliceStable(ret, func(i, j int) bool {
 		return enmap[ret[i].Service].prio < enmap[ret[j].Service].prio
 	})
@@ -0,0 +1,134 @@
+package nano
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/fumiama/imoto"
+	"github.com/sirupsen/logrus"
+)
+
+// MediaUploader 将媒体数据放入 FilePost 以便通过 PostFileToQQGroup/PostFileToQQUser 上传
+type MediaUploader interface {
+	// Upload 将 data 写入 fp, 如设置 fp.FileData 或上传至他处后设置 fp.URL
+	Upload(fp *FilePost, data []byte) error
+}
+
+// FileDataUploader 使用平台的 file_data 字段直接上传, 未设置 Bot.MediaUploader 时使用
+type FileDataUploader struct{}
+
+// Upload 实现 MediaUploader
+func (FileDataUploader) Upload(fp *FilePost, data []byte) error {
+	fp.URL = ""
+	fp.FileData = base64.StdEncoding.EncodeToString(data)
+	return nil
+}
+
+// ImotoUploader 先上传至 imoto 图床再以链接上传, 数据将经过第三方服务
+type ImotoUploader struct {
+	Token string // Token 图床的 token, 为空时匿名上传
+}
+
+// Upload 实现 MediaUploader
+func (u *ImotoUploader) Upload(fp *FilePost, data []byte) error {
+	file, _, _, err := imoto.Bed(u.Token, data)
+	if err != nil {
+		return err
+	}
+	fp.URL = file
+	fp.FileData = ""
+	return nil
+}
+
+// mediauploader 实际使用的 MediaUploader
+func (bot *Bot) mediauploader() MediaUploader {
+	if bot.MediaUploader != nil {
+		return bot.MediaUploader
+	}
+	return FileDataUploader{}
+}
+
+// resolvefilepost 将 fp.Source 解析为 URL 或经 Bot.MediaUploader 上传, 返回可直接发送的副本
+func (bot *Bot) resolvefilepost(fp *FilePost) (*FilePost, error) {
+	if fp.Source == nil {
+		return fp, nil
+	}
+	x := *fp
+	x.Source = nil
+	if u := fp.Source.URL(); u != "" {
+		x.URL, x.FileData = u, ""
+		return &x, nil
+	}
+	if err := fp.Source.checksize(); err != nil {
+		return nil, err
+	}
+	data, err := fp.Source.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &x, bot.mediauploader().Upload(&x, data)
+}
+
+// postqqfile 向当前 QQ 会话上传 fp
+func (ctx *Ctx) postqqfile(fp *FilePost) (*Message, error) {
+	switch {
+	case OnlyQQGroup(ctx):
+		return ctx.PostFileToQQGroup(ctx.Message.ChannelID, fp)
+	case OnlyQQPrivate(ctx):
+		return ctx.PostFileToQQUser(ctx.Message.Author.ID, fp)
+	}
+	return nil, ErrNoMessageContext
+}
+
+// cachedqqfile 在缓存中查找 key 的 file_info, 未命中时以 fill 生成的 FilePost 上传并缓存
+func (ctx *Ctx) cachedqqfile(key string, fill func() (*FilePost, error)) (*Message, error) {
+	cache := ctx.caller.fileinfocache()
+	if fileinfo, ok := cache.Get(key); ok {
+		logrus.Debugln(getLogHeader(), "复用已上传的媒体:", fileinfo)
+		return &Message{FileInfo: fileinfo}, nil
+	}
+	fp, err := fill()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := ctx.postqqfile(fp)
+	if err != nil {
+		return nil, err
+	}
+	if reply.FileInfo != "" {
+		cache.Set(key, reply.FileInfo, fileinfoexpire(reply.FileInfoTTL))
+	}
+	return reply, nil
+}
+
+// UploadMedia 经 Bot.MediaUploader 将 data 上传至当前 QQ 会话, 返回的 Message 带有 FileInfo
+//
+// 有效期内再次上传相同的 data 将复用 Bot.FileInfoCache 中的 file_info
+func (ctx *Ctx) UploadMedia(tp FileType, data []byte) (*Message, error) {
+	if MaxUploadSize > 0 && int64(len(data)) > MaxUploadSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrMediaTooLarge, len(data), MaxUploadSize)
+	}
+	return ctx.cachedqqfile(fileinfokey(ctx.qqscene(), tp, data), func() (*FilePost, error) {
+		fp := &FilePost{Type: tp}
+		return fp, ctx.caller.mediauploader().Upload(fp, data)
+	})
+}
+
+// uploadqqmedia 上传 src 至当前 QQ 会话, 链接形式按链接缓存, 其它形式按内容缓存
+func (ctx *Ctx) uploadqqmedia(tp FileType, src *MediaSource) (*Message, error) {
+	u := src.URL()
+	if u == "" {
+		if err := src.checksize(); err != nil {
+			return nil, err
+		}
+		data, err := src.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		return ctx.UploadMedia(tp, data)
+	}
+	u = normalizemediaurl(u)
+	return ctx.cachedqqfile(fileinfokey(ctx.qqscene()+"#url", tp, StringToBytes(u)), func() (*FilePost, error) {
+		return &FilePost{Type: tp, URL: u}, nil
+	})
+}
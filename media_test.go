@@ -0,0 +1,56 @@
+package nano
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type urlUploader struct{}
+
+func (urlUploader) Upload(fp *FilePost, data []byte) error {
+	fp.URL = "https://example.com/" + string(data)
+	return nil
+}
+
+func TestMediaUploader(t *testing.T) {
+	var files []FilePost
+	var media []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/files") {
+			var fp FilePost
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&fp))
+			files = append(files, fp)
+			_, _ = w.Write([]byte(`{"file_info":"info` + string(rune('0'+len(files))) + `","ttl":60}`))
+			return
+		}
+		var post MessagePost
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&post))
+		media = append(media, post.Media.FileInfo)
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	ctx := &Ctx{Event: Event{Type: "GroupAtMessageCreate"}, IsQQ: true, Message: &Message{ID: "media-trigger", ChannelID: "G"}, caller: bot}
+
+	_, err := ctx.SendImageBytes([]byte("png"), false)
+	assert.NoError(t, err)
+	_, err = ctx.Send(Messages{Record("base64://" + base64.StdEncoding.EncodeToString([]byte("silk"))), Video("https://example.com/a.mp4")})
+	assert.NoError(t, err)
+	bot.MediaUploader = urlUploader{}
+	_, err = ctx.SendImageBytes([]byte("b.png"), false)
+	assert.NoError(t, err)
+
+	assert.Len(t, files, 4)
+	assert.Equal(t, FilePost{Type: FileTypeImage, FileData: base64.StdEncoding.EncodeToString([]byte("png"))}, files[0])
+	assert.Equal(t, FilePost{Type: FileTypeAudio, FileData: base64.StdEncoding.EncodeToString([]byte("silk"))}, files[1])
+	assert.Equal(t, FilePost{Type: FileTypeVideo, URL: "https://example.com/a.mp4"}, files[2])
+	assert.Equal(t, FilePost{Type: FileTypeImage, URL: "https://example.com/b.png"}, files[3])
+	assert.Equal(t, []string{"info1", "info2", "info3", "info4"}, media)
+}
@@ -0,0 +1,328 @@
+package nano
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	base14 "github.com/fumiama/go-base16384"
+)
+
+var (
+	// ErrMediaIsURL 链接形式的媒体无法在本地读取
+	ErrMediaIsURL = errors.New("media source is an url")
+	// ErrMediaConsumed io.Reader 形式的媒体只能读取一次
+	ErrMediaConsumed = errors.New("media source has been consumed")
+	// ErrMediaTooLarge 媒体超过 MaxUploadSize
+	ErrMediaTooLarge = errors.New("media is too large")
+)
+
+// MaxUploadSize 单个媒体上传的字节数上限, 不大于 0 时不限制
+var MaxUploadSize int64 = 10 * 1024 * 1024
+
+// MediaSourceKind 媒体来源
+type MediaSourceKind int
+
+const (
+	MediaSourceURL    MediaSourceKind = iota // MediaSourceURL 网络链接
+	MediaSourcePath                          // MediaSourcePath 本地文件
+	MediaSourceBytes                         // MediaSourceBytes 内存数据
+	MediaSourceReader                        // MediaSourceReader 只能读取一次的 io.Reader
+	MediaSourceFS                            // MediaSourceFS fs.FS 中的文件
+)
+
+// sniffLen http.DetectContentType 最多检查的字节数
+const sniffLen = 512
+
+// MediaSource 图片、语音与视频的统一来源, 由 MediaURL, MediaFile,
+// MediaBytes, MediaReader, MediaFS 或 MediaOf 构造, 可在多个 goroutine 中共享
+type MediaSource struct {
+	kind MediaSourceKind
+	name string // 链接、路径或 fs.FS 中的文件名
+	fsys fs.FS
+
+	mu      sync.Mutex
+	data    []byte
+	encoded func() ([]byte, error) // base64:// 与 base16384:// 延迟解码
+	reader  *bufio.Reader
+	size    int64
+	mime    string
+}
+
+// MediaURL 网络链接
+func MediaURL(u string) *MediaSource {
+	return &MediaSource{kind: MediaSourceURL, name: u, size: -1}
+}
+
+// MediaFile 本地文件路径
+func MediaFile(p string) *MediaSource {
+	return &MediaSource{kind: MediaSourcePath, name: p, size: -1}
+}
+
+// MediaBytes 内存中的数据
+func MediaBytes(data []byte) *MediaSource {
+	return &MediaSource{kind: MediaSourceBytes, data: data, size: int64(len(data))}
+}
+
+// MediaReader 从 r 读取的数据, 仅能读取一次, size 未知时传 -1
+func MediaReader(r io.Reader, size int64) *MediaSource {
+	return &MediaSource{kind: MediaSourceReader, reader: bufio.NewReaderSize(r, sniffLen), size: size}
+}
+
+// MediaFS fsys 中的文件 name, 如 embed.FS
+func MediaFS(fsys fs.FS, name string) *MediaSource {
+	return &MediaSource{kind: MediaSourceFS, fsys: fsys, name: name, size: -1}
+}
+
+// MediaOf 解析 http(s) 链接与 file:///, base64://, base16384:// 形式的 file, 其余视为本地路径
+func MediaOf(file string) *MediaSource {
+	switch {
+	case hasprefixfold(file, "http://"), hasprefixfold(file, "https://"):
+		return MediaURL(file)
+	case strings.HasPrefix(file, "file:///"):
+		return MediaFile(file[8:])
+	case strings.HasPrefix(file, "base64://"):
+		m := &MediaSource{kind: MediaSourceBytes, size: -1}
+		m.encoded = func() ([]byte, error) {
+			return base64.StdEncoding.DecodeString(file[9:])
+		}
+		return m
+	case strings.HasPrefix(file, "base16384://"):
+		m := &MediaSource{kind: MediaSourceBytes, size: -1}
+		m.encoded = func() ([]byte, error) {
+			data := base14.DecodeFromString(file[12:])
+			if len(data) == 0 {
+				return nil, errors.New("invalid base16384 media")
+			}
+			return data, nil
+		}
+		return m
+	}
+	return MediaFile(file)
+}
+
+// hasprefixfold 忽略大小写的 strings.HasPrefix
+func hasprefixfold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// Kind 媒体来源
+func (m *MediaSource) Kind() MediaSourceKind {
+	return m.kind
+}
+
+// URL 链接形式媒体的链接, 其它形式返回空
+func (m *MediaSource) URL() string {
+	if m.kind != MediaSourceURL {
+		return ""
+	}
+	return m.name
+}
+
+// Name 媒体的文件名, 内存与 io.Reader 形式返回空
+func (m *MediaSource) Name() string {
+	switch m.kind {
+	case MediaSourceURL:
+		return path.Base(strings.SplitN(m.name, "?", 2)[0])
+	case MediaSourcePath:
+		return filepath.Base(m.name)
+	case MediaSourceFS:
+		return path.Base(m.name)
+	}
+	return ""
+}
+
+// decode 解码 base64:// 与 base16384:// 数据, 调用者需持有 mu
+func (m *MediaSource) decode() error {
+	if m.encoded == nil {
+		return nil
+	}
+	data, err := m.encoded()
+	if err != nil {
+		return err
+	}
+	m.data, m.size, m.encoded = data, int64(len(data)), nil
+	return nil
+}
+
+// Size 媒体的字节数, 链接与未知大小的 io.Reader 返回 -1
+func (m *MediaSource) Size() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.size >= 0 || m.kind == MediaSourceURL || m.kind == MediaSourceReader {
+		return m.size, nil
+	}
+	var (
+		info fs.FileInfo
+		err  error
+	)
+	switch m.kind {
+	case MediaSourcePath:
+		info, err = os.Stat(m.name)
+	case MediaSourceFS:
+		info, err = fs.Stat(m.fsys, m.name)
+	default:
+		err = m.decode()
+		return m.size, err
+	}
+	if err != nil {
+		return -1, err
+	}
+	m.size = info.Size()
+	return m.size, nil
+}
+
+// MIME 媒体类型, 优先由内容推断, 其次由扩展名推断, 均无法推断时为 application/octet-stream
+func (m *MediaSource) MIME() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mime != "" {
+		return m.mime, nil
+	}
+	var head []byte
+	switch m.kind {
+	case MediaSourceURL:
+	case MediaSourceReader:
+		if m.data != nil {
+			head = m.data
+			break
+		}
+		if m.reader == nil {
+			return "", ErrMediaConsumed
+		}
+		head, _ = m.reader.Peek(sniffLen)
+	case MediaSourceBytes:
+		if err := m.decode(); err != nil {
+			return "", err
+		}
+		head = m.data
+	default:
+		f, err := m.open()
+		if err != nil {
+			return "", err
+		}
+		head = make([]byte, sniffLen)
+		n, err := io.ReadFull(f, head)
+		_ = f.Close()
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return "", err
+		}
+		head = head[:n]
+	}
+	m.mime = "application/octet-stream"
+	if len(head) > 0 {
+		m.mime = http.DetectContentType(head)
+	}
+	if m.mime == "application/octet-stream" {
+		if t := mime.TypeByExtension(path.Ext(m.Name())); t != "" {
+			m.mime = t
+		}
+	}
+	return m.mime, nil
+}
+
+// open 打开媒体, 调用者需持有 mu
+func (m *MediaSource) open() (io.ReadCloser, error) {
+	switch m.kind {
+	case MediaSourceURL:
+		return nil, ErrMediaIsURL
+	case MediaSourcePath:
+		return os.Open(m.name)
+	case MediaSourceFS:
+		return m.fsys.Open(m.name)
+	case MediaSourceReader:
+		if m.data != nil {
+			return io.NopCloser(bytes.NewReader(m.data)), nil
+		}
+		if m.reader == nil {
+			return nil, ErrMediaConsumed
+		}
+		r := m.reader
+		m.reader = nil
+		return io.NopCloser(r), nil
+	}
+	if err := m.decode(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(m.data)), nil
+}
+
+// Open 打开媒体以读取, io.Reader 形式的媒体仅能打开一次
+func (m *MediaSource) Open() (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.open()
+}
+
+// Bytes 读取全部数据, io.Reader 形式的媒体读取后可再次打开
+func (m *MediaSource) Bytes() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.kind == MediaSourceBytes || m.data != nil {
+		if err := m.decode(); err != nil {
+			return nil, err
+		}
+		return m.data, nil
+	}
+	f, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if m.kind == MediaSourceReader {
+		m.data, m.size, m.reader = data, int64(len(data)), nil
+	}
+	return data, nil
+}
+
+// replayable 能否再次打开
+func (m *MediaSource) replayable() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.kind != MediaSourceReader || m.data != nil
+}
+
+// checksize 大小已知时检查是否超过 MaxUploadSize
+func (m *MediaSource) checksize() error {
+	size, err := m.Size()
+	if err != nil {
+		return err
+	}
+	if MaxUploadSize > 0 && size > MaxUploadSize {
+		return fmt.Errorf("%w: %v has %d bytes, limit is %d", ErrMediaTooLarge, m, size, MaxUploadSize)
+	}
+	return nil
+}
+
+// String 用于日志
+func (m *MediaSource) String() string {
+	switch m.kind {
+	case MediaSourceURL:
+		return m.name
+	case MediaSourcePath:
+		return "file:///" + m.name
+	case MediaSourceFS:
+		return "fs://" + m.name
+	}
+	size, _ := m.Size()
+	if size < 0 {
+		return "[数据流]"
+	}
+	return "[数据: " + strconv.FormatInt(size, 10) + " 字节]"
+}
@@ -0,0 +1,199 @@
+package nano
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pngheader = []byte("\x89PNG\x0D\x0A\x1A\x0A0000")
+
+func TestMediaSource(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "a.bin")
+	assert.NoError(t, os.WriteFile(p, pngheader, 0644))
+	fsys := fstest.MapFS{"img/b.png": &fstest.MapFile{Data: pngheader}}
+
+	for _, src := range []*MediaSource{
+		MediaOf("file:///" + p),
+		MediaOf("base64://" + base64.StdEncoding.EncodeToString(pngheader)),
+		MediaBytes(pngheader),
+		MediaReader(bytes.NewReader(pngheader), -1),
+		MediaFS(fsys, "img/b.png"),
+	} {
+		tp, err := src.MIME()
+		assert.NoError(t, err, src)
+		assert.Equal(t, "image/png", tp, src)
+		data, err := src.Bytes()
+		assert.NoError(t, err, src)
+		assert.Equal(t, pngheader, data, src)
+		size, err := src.Size()
+		assert.NoError(t, err, src)
+		assert.Equal(t, int64(len(pngheader)), size, src)
+		assert.Empty(t, src.URL())
+	}
+
+	u := MediaOf("HTTPS://example.com/c.jpg?x=1")
+	assert.Equal(t, MediaSourceURL, u.Kind())
+	assert.Equal(t, "c.jpg", u.Name())
+	_, err := u.Open()
+	assert.ErrorIs(t, err, ErrMediaIsURL)
+
+	r := MediaReader(strings.NewReader("once"), 4)
+	f, err := r.Open()
+	assert.NoError(t, err)
+	_ = f.Close()
+	_, err = r.Open()
+	assert.ErrorIs(t, err, ErrMediaConsumed)
+}
+
+func TestPostMessageWithMediaSource(t *testing.T) {
+	type part struct {
+		name, filename, contenttype string
+		data                        []byte
+	}
+	var parts []part
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		assert.NoError(t, err)
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			data, _ := io.ReadAll(p)
+			parts = append(parts, part{p.FormName(), p.FileName(), p.Header.Get("Content-Type"), data})
+		}
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	ctx := &Ctx{Event: Event{Type: "MessageCreate"}, Message: &Message{ID: "m", ChannelID: "123"}, caller: bot}
+
+	fsys := fstest.MapFS{"b.png": &fstest.MapFile{Data: pngheader}}
+	_, err := ctx.SendChain(Text("hi"), ImageOf(MediaFS(fsys, "b.png")))
+	assert.NoError(t, err)
+
+	assert.Len(t, parts, 3)
+	assert.Equal(t, part{"content", "", "application/json", []byte("hi")}, parts[0])
+	assert.Equal(t, "msg_id", parts[1].name)
+	assert.Equal(t, part{"file_image", "b.png", "image/png", pngheader}, parts[2])
+}
+
+func TestStreamMultipart(t *testing.T) {
+	var bodies [][]byte
+	var lengths []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil { // 超出大小限制时上传被客户端中断
+			return
+		}
+		bodies = append(bodies, data)
+		lengths = append(lengths, r.ContentLength)
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":1,"message":"busy"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, Backoff: &ExponentialBackoff{Base: time.Millisecond}, Statuses: []int{http.StatusServiceUnavailable}},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	fsys := fstest.MapFS{"b.png": &fstest.MapFile{Data: pngheader}}
+	_, err := bot.PostMessageToChannel("123", &MessagePost{Seq: 1, ReplyMessageID: "m", ImageSource: MediaFS(fsys, "b.png")})
+	assert.NoError(t, err)
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.Contains(t, string(bodies[1]), string(pngheader))
+	assert.Equal(t, []int64{-1, -1}, lengths)
+
+	defer func(limit int64) { MaxUploadSize = limit }(MaxUploadSize)
+	MaxUploadSize = 4
+	_, err = bot.PostMessageToChannel("123", &MessagePost{ImageSource: MediaFS(fsys, "b.png")})
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+	assert.Len(t, bodies, 2)
+	_, err = bot.PostMessageToChannel("123", &MessagePost{Seq: 1, ReplyMessageID: "m", ImageSource: MediaReader(bytes.NewReader(pngheader), -1)})
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+}
+
+func TestPostMessageImageURL(t *testing.T) {
+	var posts []MessagePost
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var post MessagePost
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&post))
+		posts = append(posts, post)
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	post := &MessagePost{ImageFile: "https://example.com/a.png"}
+	_, err := bot.PostMessageToChannel("123", post)
+	assert.NoError(t, err)
+	assert.Empty(t, post.Image) // 不修改调用者的 post
+	assert.Len(t, posts, 1)
+	assert.Equal(t, "https://example.com/a.png", posts[0].Image)
+
+	_, err = bot.PostMessageToChannel("123", &MessagePost{})
+	assert.Equal(t, ErrEmptyMessagePost, err)
+	assert.Len(t, posts, 1)
+}
+
+func TestStreamMultipartPerAttempt(t *testing.T) {
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(bodies) == 0 { // 不读取请求体即返回
+			bodies = append(bodies, nil)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies = append(bodies, data)
+		_, _ = w.Write([]byte(`{"id":"r"}`))
+	}))
+	defer srv.Close()
+	var payloads [][]byte
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, Backoff: &ExponentialBackoff{Base: time.Millisecond}, Statuses: []int{http.StatusServiceUnavailable}},
+		Interceptors: []Interceptor{func(next RoundTrip) RoundTrip {
+			return func(req *APIRequest) (*APIResponse, error) {
+				data, err := req.Payload()
+				assert.NoError(t, err)
+				payloads = append(payloads, data)
+				return next(req)
+			}
+		}},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	fsys := fstest.MapFS{"b.png": &fstest.MapFile{Data: bytes.Repeat(pngheader, 4096)}}
+	_, err := bot.PostMessageToChannel("123", &MessagePost{Seq: 1, ReplyMessageID: "m", ImageSource: MediaFS(fsys, "b.png")})
+	assert.NoError(t, err)
+	assert.Len(t, bodies, 2)
+	assert.Len(t, payloads, 2)
+	assert.Equal(t, payloads[0], payloads[1])
+	assert.Equal(t, payloads[1], bodies[1])
+}
@@ -2,6 +2,7 @@ package nano
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -36,6 +37,8 @@ const (
 	MessageSegmentTypeReply
 	MessageSegmentTypeAudio
 	MessageSegmentTypeVideo
+	MessageSegmentTypeMarkdown
+	MessageSegmentTypeKeyboard
 )
 
 // Message impl the array form of message
@@ -44,8 +47,23 @@ type Messages []MessageSegment
 // MessageSegment impl the single message
 // MessageSegment 消息数组
 type MessageSegment struct {
-	Type MessageSegmentType
-	Data string
+	Type  MessageSegmentType
+	Data  string
+	Media *MediaSource // Media 图片、语音与视频的来源, 为空时由 Data 经 MediaOf 解析
+
+	Markdown *MessageMarkdown // Markdown MessageSegmentTypeMarkdown 的内容
+	Keyboard *KeyboardBuilder // Keyboard MessageSegmentTypeKeyboard 的内容, 发送时校验
+}
+
+// source 图片、语音与视频段的媒体来源
+func (m *MessageSegment) source() *MediaSource {
+	if m.Media != nil {
+		return m.Media
+	}
+	if m.Type == MessageSegmentTypeImageBytes {
+		return MediaBytes(StringToBytes(m.Data))
+	}
+	return MediaOf(m.Data)
 }
 
 // String impls the interface fmt.Stringer
@@ -81,8 +99,18 @@ func Image(file string) MessageSegment {
 // ImageBytes 普通图片
 func ImageBytes(data []byte) MessageSegment {
 	return MessageSegment{
-		Type: MessageSegmentTypeImageBytes,
-		Data: BytesToString(data),
+		Type:  MessageSegmentTypeImageBytes,
+		Data:  BytesToString(data),
+		Media: MediaBytes(data),
+	}
+}
+
+// ImageOf 来自 src 的图片
+func ImageOf(src *MediaSource) MessageSegment {
+	return MessageSegment{
+		Type:  MessageSegmentTypeImage,
+		Data:  src.String(),
+		Media: src,
 	}
 }
 
@@ -116,21 +144,87 @@ func AtChannel(id string) MessageSegment {
 	}
 }
 
-// Record QQ 语音
+// Record QQ 语音, file 的格式同 MediaOf
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html
-func Record(url string) MessageSegment {
+func Record(file string) MessageSegment {
 	return MessageSegment{
 		Type: MessageSegmentTypeAudio,
-		Data: url,
+		Data: file,
 	}
 }
 
-// Video QQ 视频
+// RecordOf 来自 src 的 QQ 语音
+func RecordOf(src *MediaSource) MessageSegment {
+	return MessageSegment{
+		Type:  MessageSegmentTypeAudio,
+		Data:  src.String(),
+		Media: src,
+	}
+}
+
+// Video QQ 视频, file 的格式同 MediaOf
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html
-func Video(url string) MessageSegment {
+func Video(file string) MessageSegment {
 	return MessageSegment{
 		Type: MessageSegmentTypeVideo,
-		Data: url,
+		Data: file,
+	}
+}
+
+// VideoOf 来自 src 的 QQ 视频
+func VideoOf(src *MediaSource) MessageSegment {
+	return MessageSegment{
+		Type:  MessageSegmentTypeVideo,
+		Data:  src.String(),
+		Media: src,
+	}
+}
+
+// Markdown 原生 markdown, 与同一批消息中的 Keyboard 合并为一条 markdown 消息发送
+// https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/type/markdown.html
+func Markdown(content string) MessageSegment {
+	return MessageSegment{
+		Type:     MessageSegmentTypeMarkdown,
+		Data:     content,
+		Markdown: &MessageMarkdown{Content: content},
+	}
+}
+
+// MarkdownTemplate 以模版 id 与模版参数 params 发送的 markdown
+// https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/type/markdown.html
+func MarkdownTemplate(id string, params map[string][]string) MessageSegment {
+	md := &MessageMarkdown{CustomTemplateID: id}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		md.Params = append(md.Params, MessageMarkdownParams{Key: k, Values: params[k]})
+	}
+	return MessageSegment{
+		Type:     MessageSegmentTypeMarkdown,
+		Data:     "[markdown:" + id + "]",
+		Markdown: md,
+	}
+}
+
+// Keyboard 由 kb 构造的消息按钮, 须与 Markdown 或 MarkdownTemplate 一同发送
+// https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/trans/msg-btn.html
+func Keyboard(kb *KeyboardBuilder) MessageSegment {
+	return MessageSegment{
+		Type:     MessageSegmentTypeKeyboard,
+		Data:     "[keyboard]",
+		Keyboard: kb,
+	}
+}
+
+// KeyboardTemplate 以模版 id 发送的消息按钮, 须与 Markdown 或 MarkdownTemplate 一同发送
+func KeyboardTemplate(id string) MessageSegment {
+	return MessageSegment{
+		Type:     MessageSegmentTypeKeyboard,
+		Data:     "[keyboard:" + id + "]",
+		Keyboard: &KeyboardBuilder{id: id},
 	}
 }
 
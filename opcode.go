@@ -15,10 +15,11 @@ const (
 	OpCodeResume    // Send
 	OpCodeReconnect // Receive
 	OpCodeEmpty4
-	OpCodeInvalidSession  // Receive
-	OpCodeHello           // Receive
-	OpCodeHeartbeatACK    // Receive/Reply
-	OpCodeHTTPCallbackACK // Reply
+	OpCodeInvalidSession         // Receive
+	OpCodeHello                  // Receive
+	OpCodeHeartbeatACK           // Receive/Reply
+	OpCodeHTTPCallbackACK        // Reply
+	OpCodeHTTPCallbackValidation // Receive
 )
 
 // OpCodeIdentifyMessage https://bot.q.qq.com/wiki/develop/api/gateway/reference.html#_2-%E9%89%B4%E6%9D%83%E8%BF%9E%E6%8E%A5
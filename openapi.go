@@ -1,15 +1,15 @@
 package nano
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"net/http"
 	"reflect"
-	"strconv"
-	"strings"
 	"unsafe"
 
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -22,7 +22,7 @@ const (
 )
 
 var (
-	OpenAPI = StandardAPI // OpenAPI 实际使用的 API, 默认 StandardAPI, 可自行赋值配置
+	OpenAPI = StandardAPI // OpenAPI 未设置 Bot.APIBase 时使用的 API, 默认 StandardAPI, 可自行赋值配置
 )
 
 // CodeMessageBase 各种消息都有的 code + message 基类
@@ -31,67 +31,134 @@ type CodeMessageBase struct {
 	M string `json:"message"`
 }
 
+// dohttprequest 发送请求, 按路由限流, Token 失效时刷新 Token 并重试一次,
+// 429 时等待后重试, 临时错误按 RetryPolicy 重试, 每次重试都从头重读 body
 func (bot *Bot) dohttprequest(constructer HTTPRequsetConstructer, ep, contenttype string, ptr any, body io.Reader) error {
+	caller := getCallerFuncName()
+	ib, isidempotent := body.(idempotentBody)
+	if isidempotent {
+		body = ib.Reader
+	}
+	if buf, ok := body.(*bytes.Buffer); ok {
+		body = bytes.NewReader(buf.Bytes()) // 以便重试时重读
+	}
+	stream, _ := body.(*multipartStream) // 每次请求重新生成, 无需 Seek
+	seeker, _ := body.(io.Seeker)
+	var start int64
+	if seeker != nil {
+		var err error
+		start, err = seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			seeker = nil
+		}
+	}
+	policy := bot.retrypolicy()
+	attempt, limited, refreshed := 1, 0, false
+	for {
+		stale := bot.accesstoken()
+		method, err := bot.dohttprequestonce(caller, constructer, ep, contenttype, ptr, body)
+		if err == nil || (body != nil && seeker == nil && (stream == nil || !stream.replayable)) {
+			return err
+		}
+		var apierr *APIError
+		isapierr := errors.As(err, &apierr)
+		switch {
+		case isapierr && apierr.Status == http.StatusTooManyRequests && limited < maxRateLimitRetry:
+			limited++
+			log.Warnln(getLogHeader(), "触发限流, 等待后第", limited, "次重试:", err)
+		case isapierr && bot.IsV2() && !refreshed && apierr.Kind() == APIErrorTokenExpired:
+			refreshed = true
+			log.Warnln(getLogHeader(), "Token 已失效, 刷新后重试:", err)
+			if rerr := bot.refreshaccesstoken(stale); rerr != nil {
+				return errors.Wrap(rerr, caller)
+			}
+		case attempt < policy.MaxAttempts && policy.retryable(method, isidempotent, err):
+			log.Warnln(getLogHeader(), method, ep, "第", attempt, "次请求失败, 稍后重试:", err)
+			if !bot.sleep(policy.backoff(attempt)) {
+				return err
+			}
+			attempt++
+		default:
+			return err
+		}
+		if seeker != nil {
+			if _, serr := seeker.Seek(start, io.SeekStart); serr != nil {
+				return errors.Wrap(serr, caller)
+			}
+		}
+		if ptr != nil {
+			reflect.ValueOf(ptr).Elem().SetZero() // 清除失败时解析到的错误码
+		}
+	}
+}
+
+// dohttprequestonce 等待限流后经过拦截器链发送一次请求, 返回请求方法
+func (bot *Bot) dohttprequestonce(caller string, constructer HTTPRequsetConstructer, ep, contenttype string, ptr any, body io.Reader) (method string, err error) {
 	appid := ""
 	if bot.IsV2() {
 		appid = bot.AppID
 	}
-	req, err := constructer(ep, contenttype, bot.Authorization(), appid, body)
+	stream, _ := body.(*multipartStream)
+	if stream != nil {
+		rc := stream.open() // 每次请求使用独立的管道
+		defer rc.Close()
+		body = rc
+	}
+	req, err := constructer(bot.apibase(), ep, contenttype, bot.Authorization(), appid, body)
 	if err != nil {
-		return errors.Wrap(err, getCallerFuncName())
+		return "", errors.Wrap(err, caller)
+	}
+	if stream != nil && stream.replayable {
+		req.GetBody = stream.getbody
 	}
-	resp, err := bot.client.Do(req)
+	method = req.Method
+	ctx, cancel := bot.requestcontext()
+	defer cancel()
+	req = req.WithContext(ctx)
+	route, target := ratelimitroute(ep)
+	key, release, err := bot.waitratelimit(ctx, req.Method, route, target)
 	if err != nil {
-		return errors.Wrap(err, getCallerFuncName())
+		return method, errors.Wrap(err, caller)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusNoContent {
-		return nil
+	defer release()
+	resp, err := bot.interceptedroundtrip()(&APIRequest{
+		Request: req, Route: req.Method + " " + route, Target: target, Caller: caller,
+	})
+	if err != nil {
+		return method, errors.Wrap(err, caller)
 	}
-	errsb := strings.Builder{}
-	var respbase *CodeMessageBase
-	if resp.StatusCode >= http.StatusBadRequest {
-		errsb.WriteString("code: ")
-		errsb.WriteString(resp.Status)
+	bot.ratelimiter().update(key, resp.Response)
+	if resp.StatusCode == http.StatusNoContent {
+		return method, nil
 	}
-	if ptr == nil {
-		goto RET
+	apierr := &APIError{
+		Status:   resp.StatusCode,
+		Method:   req.Method,
+		Endpoint: ep,
+		TraceID:  resp.Header.Get("X-Tps-trace-ID"),
 	}
-	err = json.NewDecoder(resp.Body).Decode(ptr)
-	if err != nil {
-		if errsb.Len() > 0 {
-			errsb.WriteString(", ")
+	data := resp.Data
+	var respbase *CodeMessageBase
+	if ptr != nil {
+		apierr.Err = json.Unmarshal(data, ptr)
+		if apierr.Err == nil && reflect.ValueOf(ptr).Elem().Kind() != reflect.Slice {
+			respbase = (*CodeMessageBase)(*(*unsafe.Pointer)(unsafe.Add(unsafe.Pointer(&ptr), unsafe.Sizeof(uintptr(0)))))
 		}
-		errsb.WriteString("json: ")
-		errsb.WriteString(err.Error())
-		goto RET
 	}
-	if reflect.ValueOf(ptr).Elem().Kind() == reflect.Slice {
-		return nil
+	if respbase == nil && resp.StatusCode >= http.StatusBadRequest {
+		respbase = &CodeMessageBase{}
+		_ = json.Unmarshal(data, respbase) // 尽量获得错误码
 	}
-	respbase = (*CodeMessageBase)(*(*unsafe.Pointer)(unsafe.Add(unsafe.Pointer(&ptr), unsafe.Sizeof(uintptr(0)))))
-	if respbase.C != 0 {
-		if errsb.Len() > 0 {
-			errsb.WriteString(", ")
-		}
-		errsb.WriteString("err: [")
-		errsb.WriteString(strconv.Itoa(respbase.C))
-		errsb.WriteString("] ")
-		if len([]rune(respbase.M)) > 256 {
-			errsb.WriteString(string([]rune(respbase.M)[:256]))
-			errsb.WriteString("...")
-		} else {
-			errsb.WriteString(respbase.M)
-		}
+	if respbase != nil {
+		apierr.Code, apierr.Message = respbase.C, respbase.M
 	}
-RET:
-	if errsb.Len() > 0 {
-		return errors.Wrap(errors.New(errsb.String()), getCallerFuncName())
+	if resp.StatusCode < http.StatusBadRequest && apierr.Err == nil && apierr.Code == 0 {
+		return method, nil
 	}
-	return nil
+	return method, errors.Wrap(apierr, caller)
 }
 
-//go:generate go run codegen/getopenapiof/main.go ShardWSSGateway User Guild Channel Member RoleMembers GuildRoleList ChannelPermissions Message MessageSetting PinsMessage Schedule MessageReactionUsers
+//go:generate go run codegen/getopenapiof/main.go ShardWSSGateway User Guild Channel Member RoleMembers GuildRoleList ChannelPermissions Message MessageSetting PinsMessage Schedule MessageReactionUsers APIPermissions
 
 // GetOpenAPI 从 ep 获取 json 结构化数据写到 ptr, ptr 除 Slice 外必须在开头继承 CodeMessageBase
 func (bot *Bot) GetOpenAPI(ep, contenttype string, ptr any) error {
@@ -120,7 +187,7 @@ func (bot *Bot) DeleteOpenAPIWithPtr(ep, contenttype string, ptr any, body io.Re
 	return bot.dohttprequest(NewHTTPEndpointDeleteRequestWithAuth, ep, contenttype, ptr, body)
 }
 
-//go:generate go run codegen/postopenapiof/main.go Channel GuildRoleCreate Message DMS
+//go:generate go run codegen/postopenapiof/main.go Channel GuildRoleCreate Message DMS APIPermissionDemand
 
 // PostOpenAPI 从 ep 得到 json 结构化数据返回值写到 ptr, ptr 除 Slice 外必须在开头继承 CodeMessageBase
 func (bot *Bot) PostOpenAPI(ep, contenttype string, ptr any, body io.Reader) error {
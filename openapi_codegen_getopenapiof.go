@@ -161,3 +161,15 @@ func (bot *Bot) getOpenAPIofMessageReactionUsers(ep string) (*MessageReactionUse
 	}
 	return &resp.MessageReactionUsers, err
 }
+
+func (bot *Bot) getOpenAPIofAPIPermissions(ep string) (*APIPermissions, error) {
+	resp := &struct {
+		CodeMessageBase
+		APIPermissions
+	}{}
+	err := bot.GetOpenAPI(ep, "", resp)
+	if err != nil {
+		err = errors.Wrap(err, getCallerFuncName())
+	}
+	return &resp.APIPermissions, err
+}
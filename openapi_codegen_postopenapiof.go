@@ -55,3 +55,15 @@ func (bot *Bot) postOpenAPIofDMS(ep, contenttype string, body io.Reader) (*DMS,
 	}
 	return &resp.DMS, err
 }
+
+func (bot *Bot) postOpenAPIofAPIPermissionDemand(ep, contenttype string, body io.Reader) (*APIPermissionDemand, error) {
+	resp := &struct {
+		CodeMessageBase
+		APIPermissionDemand
+	}{}
+	err := bot.PostOpenAPI(ep, contenttype, resp, body)
+	if err != nil {
+		err = errors.Wrap(err, getCallerFuncName())
+	}
+	return &resp.APIPermissionDemand, err
+}
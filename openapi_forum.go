@@ -1,6 +1,9 @@
 package nano
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Thread 话题频道内发表的主帖称为主题
 //
@@ -20,6 +23,24 @@ type ThreadInfo struct {
 	Title    string    `json:"title"`
 	Content  string    `json:"content"`
 	DateTime time.Time `json:"date_time"`
+	Rich     *RichText `json:"-"` // Rich 解析自 Content 的富文本, Content 不是富文本时为 nil
+}
+
+// unmarshalrich 将 data 解析到 v, 再将其中的 content 解析为富文本写入 rich
+func unmarshalrich(data []byte, v any, content *string, rich **RichText) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if rt, err := ParseRichText(*content); err == nil && len(rt.Paragraphs) > 0 {
+		*rich = rt
+	}
+	return nil
+}
+
+// UnmarshalJSON 同时解析 Content 中的富文本到 Rich
+func (x *ThreadInfo) UnmarshalJSON(data []byte) error {
+	type raw ThreadInfo
+	return unmarshalrich(data, (*raw)(x), &x.Content, &x.Rich)
 }
 
 // Post 话题频道内对主题的评论称为帖子
@@ -40,6 +61,13 @@ type PostInfo struct {
 	PostID   string    `json:"post_id"`
 	Content  string    `json:"content"`
 	DateTime time.Time `json:"date_time"`
+	Rich     *RichText `json:"-"` // Rich 解析自 Content 的富文本, Content 不是富文本时为 nil
+}
+
+// UnmarshalJSON 同时解析 Content 中的富文本到 Rich
+func (x *PostInfo) UnmarshalJSON(data []byte) error {
+	type raw PostInfo
+	return unmarshalrich(data, (*raw)(x), &x.Content, &x.Rich)
 }
 
 // Reply 话题频道对帖子回复或删除时生产该事件中包含该对象
@@ -61,6 +89,13 @@ type ReplyInfo struct {
 	ReplyID  string    `json:"reply_id"`
 	Content  string    `json:"content"`
 	DateTime time.Time `json:"date_time"`
+	Rich     *RichText `json:"-"` // Rich 解析自 Content 的富文本, Content 不是富文本时为 nil
+}
+
+// UnmarshalJSON 同时解析 Content 中的富文本到 Rich
+func (x *ReplyInfo) UnmarshalJSON(data []byte) error {
+	type raw ReplyInfo
+	return unmarshalrich(data, (*raw)(x), &x.Content, &x.Rich)
 }
 
 // AuditResult 论坛帖子审核结果事件
@@ -78,6 +113,14 @@ type AuditResult struct {
 	ErrMsg    string `json:"err_msg"`
 }
 
+// 发表帖子时 content 的格式
+const (
+	ThreadFormatText     uint32 = 1 + iota // ThreadFormatText 普通文本
+	ThreadFormatHTML                       // ThreadFormatHTML HTML
+	ThreadFormatMarkdown                   // ThreadFormatMarkdown Markdown
+	ThreadFormatJSON                       // ThreadFormatJSON json, 即 RichText
+)
+
 // GetChannelThreads 获取子频道下的帖子列表
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/get_threads_list.html
@@ -124,6 +167,17 @@ func (bot *Bot) PostThreadInChannel(id string, title string, content string, for
 	return
 }
 
+// PostRichThreadInChannel 以 json 格式发表富文本帖子
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/put_thread.html
+func (bot *Bot) PostRichThreadInChannel(id string, title string, content *RichText) (taskid string, createtime string, err error) {
+	c, err := content.Encode()
+	if err != nil {
+		return
+	}
+	return bot.PostThreadInChannel(id, title, c, ThreadFormatJSON)
+}
+
 // DeleteThreadInChannel 删除指定子频道下的某个帖子
 //
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/delete_thread.html
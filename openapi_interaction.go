@@ -0,0 +1,94 @@
+package nano
+
+// InteractionType 互动事件类型
+type InteractionType int
+
+const (
+	// InteractionTypeButton 消息按钮
+	InteractionTypeButton InteractionType = 11
+	// InteractionTypeSelect 单聊快捷菜单
+	InteractionTypeSelect InteractionType = 12
+)
+
+// InteractionChatType 互动事件发生的场景
+type InteractionChatType int
+
+const (
+	InteractionChatTypeGuild InteractionChatType = iota // InteractionChatTypeGuild 频道
+	InteractionChatTypeGroup                            // InteractionChatTypeGroup QQ 群
+	InteractionChatTypeC2C                              // InteractionChatTypeC2C QQ 单聊
+)
+
+// Interaction 互动事件, 如点击回调按钮
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/dev-prepare/interface-framework/event-emit.html#%E4%BA%92%E5%8A%A8%E4%BA%8B%E4%BB%B6
+type Interaction struct {
+	ID                string              `json:"id"` // ID 用于 PutInteractionResult
+	Type              InteractionType     `json:"type"`
+	Scene             string              `json:"scene"` // Scene guild, group 或 c2c
+	ChatType          InteractionChatType `json:"chat_type"`
+	Timestamp         string              `json:"timestamp"`
+	GuildID           string              `json:"guild_id"`
+	ChannelID         string              `json:"channel_id"`
+	UserOpenID        string              `json:"user_openid"`
+	GroupOpenID       string              `json:"group_openid"`
+	GroupMemberOpenID string              `json:"group_member_openid"`
+	Data              struct {
+		Type     InteractionType `json:"type"`
+		Resolved struct {
+			ButtonData string `json:"button_data"`
+			ButtonID   string `json:"button_id"`
+			UserID     string `json:"user_id"`
+			FeatureID  string `json:"feature_id"`
+			MessageID  string `json:"message_id"`
+		} `json:"resolved"`
+	} `json:"data"`
+	Version       int    `json:"version"`
+	ApplicationID string `json:"application_id"`
+
+	acked uint32 // acked 是否已回应
+}
+
+// ButtonID 点击的按钮 ID
+func (it *Interaction) ButtonID() string {
+	return it.Data.Resolved.ButtonID
+}
+
+// ButtonData 点击的按钮 action.data
+func (it *Interaction) ButtonData() string {
+	return it.Data.Resolved.ButtonData
+}
+
+// message 由互动事件的场景构造的 Message, ID 为互动事件 ID, 用于在 Ctx 中回复
+func (it *Interaction) message() *Message {
+	msg := &Message{ID: it.ID, GuildID: it.GuildID, ChannelID: it.ChannelID, Author: &User{ID: it.Data.Resolved.UserID}}
+	switch it.ChatType {
+	case InteractionChatTypeGroup:
+		msg.GroupOpenID, msg.ChannelID = it.GroupOpenID, it.GroupOpenID
+		msg.Author.ID = it.GroupMemberOpenID
+	case InteractionChatTypeC2C:
+		msg.ChannelID, msg.Author.ID = it.UserOpenID, it.UserOpenID
+	}
+	return msg
+}
+
+// InteractionResultCode 回应互动事件的结果
+type InteractionResultCode int
+
+const (
+	InteractionResultSuccess      InteractionResultCode = iota // InteractionResultSuccess 成功
+	InteractionResultFailed                                    // InteractionResultFailed 操作失败
+	InteractionResultTooFrequent                               // InteractionResultTooFrequent 操作频繁
+	InteractionResultDuplicated                                // InteractionResultDuplicated 重复操作
+	InteractionResultNoPermission                              // InteractionResultNoPermission 没有权限
+	InteractionResultOnlyAdmin                                 // InteractionResultOnlyAdmin 仅管理员操作
+)
+
+// PutInteractionResult 回应互动事件 interaction_id, 否则用户客户端将一直显示加载中
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/trans/msg-btn.html#%E5%9B%9E%E8%B0%83%E6%93%8D%E4%BD%9C
+func (bot *Bot) PutInteractionResult(id string, code InteractionResultCode) error {
+	return bot.PutOpenAPI("/interactions/"+id, "", nil, WriteBodyFromJSON(&struct {
+		C InteractionResultCode `json:"code"`
+	}{code}))
+}
@@ -2,6 +2,7 @@ package nano
 
 import (
 	"encoding/json"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
@@ -12,6 +13,7 @@ import (
 )
 
 var (
+	// ErrEmptyMessagePost 消息没有任何可发送的内容, 发送消息的接口此时直接返回该错误而不请求 API
 	ErrEmptyMessagePost = errors.New("empty message post")
 )
 
@@ -259,6 +261,7 @@ type MessagePost struct {
 	Image            string            `json:"image,omitempty"`
 	ImageFile        string            `json:"-"` // ImageFile 为图片路径 file:/// or base64:// or base16384:// , 与 Image, ImageBytes 参数二选一, 优先 ImageBytes
 	ImageBytes       []byte            `json:"-"` // ImageBytes 图片数据
+	ImageSource      *MediaSource      `json:"-"` // ImageSource 图片来源, 优先于 ImageBytes 与 ImageFile
 	ReplyMessageID   string            `json:"msg_id,omitempty"`
 	ReplyEventID     string            `json:"event_id,omitempty"`
 	Markdown         *MessageMarkdown  `json:"markdown,omitempty"`
@@ -320,13 +323,36 @@ func (mp *MessagePost) String() string {
 		sb.WriteString(", 图片大小: ")
 		sb.WriteString(strconv.Itoa(len(mp.ImageBytes)))
 	}
+	if mp.ImageSource != nil {
+		sb.WriteString(", 图片来源: ")
+		sb.WriteString(mp.ImageSource.String())
+	}
 	if mp.Markdown != nil {
-		sb.WriteString(", MD模版: ")
-		sb.WriteString(strconv.Itoa(mp.Markdown.TemplateID))
+		switch {
+		case mp.Markdown.Content != "":
+			sb.WriteString(", MD: ")
+			x := mp.Markdown.Content
+			if len(x) > 64 {
+				x = x[:64] + "..."
+			}
+			sb.WriteString(x)
+		case mp.Markdown.CustomTemplateID != "":
+			sb.WriteString(", MD模版: ")
+			sb.WriteString(mp.Markdown.CustomTemplateID)
+		default:
+			sb.WriteString(", MD模版: ")
+			sb.WriteString(strconv.Itoa(mp.Markdown.TemplateID))
+		}
 	}
 	if mp.KeyBoard != nil {
-		sb.WriteString(", KB模版: ")
-		sb.WriteString(mp.KeyBoard.ID)
+		if mp.KeyBoard.Content != nil {
+			sb.WriteString(", KB: ")
+			sb.WriteString(strconv.Itoa(len(mp.KeyBoard.Content.Rows)))
+			sb.WriteString(" 行")
+		} else {
+			sb.WriteString(", KB模版: ")
+			sb.WriteString(mp.KeyBoard.ID)
+		}
 	}
 	if mp.Media != nil {
 		sb.WriteString(", 富媒体: ")
@@ -340,21 +366,59 @@ func (mp *MessagePost) String() string {
 	return sb.String()
 }
 
+// body 带有 msg_id 与 msg_seq 的消息重复发送会被平台拒绝, 可安全重试
+func (content *MessagePost) body(body io.Reader) io.Reader {
+	if content.ReplyMessageID != "" && content.Seq > 0 {
+		return idempotent(body)
+	}
+	return body
+}
+
+// imagesource 以 multipart/form-data 上传的图片, 来源为链接时返回链接而非来源
+func (content *MessagePost) imagesource() (src *MediaSource, image string) {
+	src = content.ImageSource
+	switch {
+	case src != nil:
+	case len(content.ImageBytes) > 0:
+		src = MediaBytes(content.ImageBytes)
+	case content.ImageFile != "":
+		src = MediaOf(content.ImageFile)
+	default:
+		return nil, ""
+	}
+	if u := src.URL(); u != "" {
+		return nil, u
+	}
+	return src, ""
+}
+
+// isempty 除图片来源外没有任何可发送的内容
+func (content *MessagePost) isempty() bool {
+	return content.Content == "" && content.Embed == nil && content.Ark == nil && content.Image == "" &&
+		content.Markdown == nil && content.KeyBoard == nil && content.Media == nil
+}
+
 func (bot *Bot) postMessageTo(ep string, content *MessagePost) (*Message, error) {
-	if len(content.ImageBytes) == 0 && content.ImageFile == "" {
-		return bot.postOpenAPIofMessage(ep, "", WriteBodyFromJSON(content))
+	src, image := content.imagesource()
+	if image != "" {
+		x := *content
+		x.Image = image
+		content = &x
+	}
+	if src == nil {
+		if content.isempty() {
+			return nil, ErrEmptyMessagePost
+		}
+		return bot.postOpenAPIofMessage(ep, "", content.body(WriteBodyFromJSON(content)))
 	}
 	x := reflect.ValueOf(content).Elem()
 	t := x.Type()
 	msg := []any{}
 	for i := 0; i < x.NumField(); i++ {
 		xi := x.Field(i)
-		if xi.IsZero() {
-			continue
-		}
 		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
-		if tag == "-" {
-			tag = "file_image"
+		if xi.IsZero() || tag == "-" {
+			continue
 		}
 		msg = append(msg, tag)
 		if xi.Kind() == reflect.Pointer && xi.Elem().Kind() == reflect.Struct {
@@ -365,16 +429,14 @@ func (bot *Bot) postMessageTo(ep string, content *MessagePost) (*Message, error)
 			msg = append(msg, data)
 			continue
 		}
-		msg = append(msg, xi.Interface()) // []byte or string
-	}
-	if len(msg) < 2 {
-		return nil, ErrEmptyMessagePost
+		msg = append(msg, xi.Interface())
 	}
-	body, contenttype, err := WriteBodyByMultipartFormData(msg...)
+	msg = append(msg, "file_image", src)
+	body, contenttype, err := StreamBodyByMultipartFormData(msg...)
 	if err != nil {
 		return nil, errors.Wrap(err, getThisFuncName())
 	}
-	m, err := bot.postOpenAPIofMessage(ep, contenttype, body)
+	m, err := bot.postOpenAPIofMessage(ep, contenttype, content.body(body))
 	if err != nil {
 		return nil, errors.Wrap(err, getThisFuncName())
 	}
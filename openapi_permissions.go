@@ -1,3 +1,59 @@
 package nano
 
-// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/model.html
+// APIPermission 接口权限对象
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/model.html#apipermission
+type APIPermission struct {
+	Path       string `json:"path"`
+	Method     string `json:"method"`
+	Desc       string `json:"desc"`
+	AuthStatus int    `json:"auth_status"` // 授权状态，auth_stats 为 1 时已授权
+}
+
+// APIPermissions 频道可用权限列表
+type APIPermissions struct {
+	APIs []APIPermission `json:"apis"`
+}
+
+// APIPermissionDemandIdentify 接口权限需求标识对象
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/model.html#apipermissiondemandidentify
+type APIPermissionDemandIdentify struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+}
+
+// APIPermissionDemand 接口权限需求对象
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/model.html#apipermissiondemand
+type APIPermissionDemand struct {
+	GuildID     string                      `json:"guild_id"`
+	ChannelID   string                      `json:"channel_id"`
+	APIIdentify APIPermissionDemandIdentify `json:"api_identify"`
+	Title       string                      `json:"title"`
+	Desc        string                      `json:"desc"`
+}
+
+// GetAPIPermissionsOfGuild 获取机器人在频道 guild_id 内可以使用的权限列表
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/get_guild_api_permission.html
+func (bot *Bot) GetAPIPermissionsOfGuild(id string) ([]APIPermission, error) {
+	perms, err := bot.getOpenAPIofAPIPermissions("/guilds/" + id + "/api_permission")
+	if err != nil {
+		return nil, err
+	}
+	return perms.APIs, nil
+}
+
+// PostAPIPermissionDemandInGuild 发送机器人在频道 guild_id 内的接口权限授权链接到子频道 channel_id
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/api_permissions/post_api_permission_demand.html
+//
+// desc 为机器人申请对应的 API 接口权限后可以使用功能的描述
+func (bot *Bot) PostAPIPermissionDemandInGuild(guildid, channelid string, api APIPermissionDemandIdentify, desc string) (*APIPermissionDemand, error) {
+	return bot.postOpenAPIofAPIPermissionDemand("/guilds/"+guildid+"/api_permission/demand", "", WriteBodyFromJSON(&struct {
+		C string                      `json:"channel_id"`
+		A APIPermissionDemandIdentify `json:"api_identify"`
+		D string                      `json:"desc"`
+	}{channelid, api, desc}))
+}
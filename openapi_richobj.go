@@ -1,3 +1,266 @@
 package nano
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // https://bot.q.qq.com/wiki/develop/api/openapi/forum/get_threads_list.html
+
+// RichText 帖子的富文本内容, 以 json 字符串形式存放于 Content
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/model.html#richtext
+type RichText struct {
+	Paragraphs []Paragraph `json:"paragraphs"`
+}
+
+// Paragraph 富文本段落
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/model.html#paragraph
+type Paragraph struct {
+	Elems []Elem          `json:"elems"`
+	Props *ParagraphProps `json:"props,omitempty"`
+}
+
+// ParagraphProps 段落属性
+type ParagraphProps struct {
+	Alignment uint32 `json:"alignment"` // 0:左对齐 1:居中 2:右对齐
+}
+
+// ElemType 富文本元素类型
+type ElemType uint32
+
+const (
+	ElemTypeText  ElemType = 1 + iota // ElemTypeText 文本
+	ElemTypeImage                     // ElemTypeImage 图片
+	ElemTypeVideo                     // ElemTypeVideo 视频
+	ElemTypeURL                       // ElemTypeURL 链接
+)
+
+// Elem 富文本元素, 按 Type 只有一个字段有值
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/model.html#elem
+type Elem struct {
+	Text  *TextElem  `json:"text,omitempty"`
+	Image *ImageElem `json:"image,omitempty"`
+	Video *VideoElem `json:"video,omitempty"`
+	URL   *URLElem   `json:"url,omitempty"`
+	Type  ElemType   `json:"type"`
+}
+
+// TextElem 文本元素
+type TextElem struct {
+	Text  string     `json:"text"`
+	Props *TextProps `json:"props,omitempty"`
+}
+
+// TextProps 文本属性
+type TextProps struct {
+	FontBold  bool `json:"font_bold,omitempty"`
+	Italic    bool `json:"italic,omitempty"`
+	Underline bool `json:"underline,omitempty"`
+}
+
+// ImageElem 图片元素
+type ImageElem struct {
+	ThirdURL     string  `json:"third_url"`
+	WidthPercent float64 `json:"width_percent,omitempty"` // 在屏幕中显示的宽度比例
+}
+
+// VideoElem 视频元素
+type VideoElem struct {
+	ThirdURL string `json:"third_url"`
+}
+
+// URLElem 链接元素
+type URLElem struct {
+	URL  string `json:"url"`
+	Desc string `json:"desc"`
+}
+
+// TextElemOf 生成文本元素
+func TextElemOf(text string) Elem {
+	return Elem{Text: &TextElem{Text: text}, Type: ElemTypeText}
+}
+
+// ImageElemOf 生成图片元素
+func ImageElemOf(url string) Elem {
+	return Elem{Image: &ImageElem{ThirdURL: url}, Type: ElemTypeImage}
+}
+
+// VideoElemOf 生成视频元素
+func VideoElemOf(url string) Elem {
+	return Elem{Video: &VideoElem{ThirdURL: url}, Type: ElemTypeVideo}
+}
+
+// URLElemOf 生成链接元素
+func URLElemOf(url, desc string) Elem {
+	return Elem{URL: &URLElem{URL: url, Desc: desc}, Type: ElemTypeURL}
+}
+
+// ParseRichText 解析 Content 中的富文本
+func ParseRichText(content string) (*RichText, error) {
+	rt := &RichText{}
+	err := json.Unmarshal(StringToBytes(content), rt)
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// Encode 编码为可发表的 Content
+func (rt *RichText) Encode() (string, error) {
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return "", err
+	}
+	return BytesToString(data), nil
+}
+
+// PlainText 提取纯文本, 段落间以换行分隔, 链接取其描述, 图片与视频被忽略
+func (rt *RichText) PlainText() string {
+	sb := strings.Builder{}
+	for i, p := range rt.Paragraphs {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for _, e := range p.Elems {
+			switch {
+			case e.Text != nil:
+				sb.WriteString(e.Text.Text)
+			case e.URL != nil && e.URL.Desc != "":
+				sb.WriteString(e.URL.Desc)
+			case e.URL != nil:
+				sb.WriteString(e.URL.URL)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// RichType 富文本对象类型
+type RichType uint32
+
+const (
+	RichTypeText    RichType = 1 + iota // RichTypeText 普通文本
+	RichTypeAt                          // RichTypeAt at 信息
+	RichTypeURL                         // RichTypeURL url 信息
+	RichTypeEmoji                       // RichTypeEmoji 表情
+	RichTypeChannel                     // RichTypeChannel #子频道
+)
+
+// RichObject 富文本对象
+//
+// https://bot.q.qq.com/wiki/develop/api/openapi/forum/model.html#richobject
+type RichObject struct {
+	Type        RichType         `json:"type"`
+	TextInfo    *RichTextInfo    `json:"text_info,omitempty"`
+	AtInfo      *RichAtInfo      `json:"at_info,omitempty"`
+	URLInfo     *RichURLInfo     `json:"url_info,omitempty"`
+	EmojiInfo   *RichEmojiInfo   `json:"emoji_info,omitempty"`
+	ChannelInfo *RichChannelInfo `json:"channel_info,omitempty"`
+}
+
+// RichTextInfo 普通文本
+type RichTextInfo struct {
+	Text string `json:"text"`
+}
+
+// AtType at 类型
+type AtType uint32
+
+const (
+	AtTypeUser     AtType = 1 + iota // AtTypeUser at 特定人
+	AtTypeRole                       // AtTypeRole at 身份组所有人
+	AtTypeEveryone                   // AtTypeEveryone at 频道所有人
+)
+
+// RichAtInfo at 信息
+type RichAtInfo struct {
+	Type     AtType `json:"type"`
+	UserInfo *struct {
+		ID   string `json:"id"`
+		Nick string `json:"nick"`
+	} `json:"user_info,omitempty"`
+	RoleInfo *struct {
+		RoleID uint64 `json:"role_id"`
+		Name   string `json:"name"`
+		Color  uint32 `json:"color"`
+	} `json:"role_info,omitempty"`
+	GuildInfo *struct {
+		GuildID   string `json:"guild_id"`
+		GuildName string `json:"guild_name"`
+	} `json:"guild_info,omitempty"`
+}
+
+// RichURLInfo 链接信息
+type RichURLInfo struct {
+	URL         string `json:"url"`
+	DisplayText string `json:"display_text"`
+}
+
+// RichEmojiInfo 表情信息
+type RichEmojiInfo struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// RichChannelInfo 子频道信息
+type RichChannelInfo struct {
+	ChannelID   uint64 `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+}
+
+// PlainText 提取纯文本, at 与子频道以 @名称 与 #名称 表示
+func (ro *RichObject) PlainText() string {
+	switch {
+	case ro.TextInfo != nil:
+		return ro.TextInfo.Text
+	case ro.AtInfo != nil && ro.AtInfo.UserInfo != nil:
+		return "@" + ro.AtInfo.UserInfo.Nick
+	case ro.AtInfo != nil && ro.AtInfo.RoleInfo != nil:
+		return "@" + ro.AtInfo.RoleInfo.Name
+	case ro.AtInfo != nil:
+		return "@全体成员"
+	case ro.URLInfo != nil && ro.URLInfo.DisplayText != "":
+		return ro.URLInfo.DisplayText
+	case ro.URLInfo != nil:
+		return ro.URLInfo.URL
+	case ro.EmojiInfo != nil:
+		return ro.EmojiInfo.Name
+	case ro.ChannelInfo != nil:
+		return "#" + ro.ChannelInfo.ChannelName
+	}
+	return ""
+}
+
+// richtextof 帖子、评论与回复事件的纯文本内容
+func richtextof(v any) (string, bool) {
+	var content string
+	var rich *RichText
+	switch x := v.(type) {
+	case *Thread:
+		if x.ThreadInfo == nil {
+			return "", false
+		}
+		content, rich = x.ThreadInfo.Content, x.ThreadInfo.Rich
+	case *Post:
+		if x.PostInfo == nil {
+			return "", false
+		}
+		content, rich = x.PostInfo.Content, x.PostInfo.Rich
+	case *Reply:
+		if x.ReplyInfo == nil {
+			return "", false
+		}
+		content, rich = x.ReplyInfo.Content, x.ReplyInfo.Rich
+	default:
+		return "", false
+	}
+	if rich != nil {
+		return rich.PlainText(), true
+	}
+	return content, true
+}
@@ -1,9 +1,11 @@
 package nano
 
 import (
+	"encoding/base64"
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -36,10 +38,11 @@ func (ft FileType) String() string {
 //
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html
 type FilePost struct {
-	Type       FileType `json:"file_type"`
-	URL        string   `json:"url"`
-	IsPositive bool     `json:"srv_send_msg"` // IsPositive
-	// file_data		否	【暂未支持】
+	Type       FileType     `json:"file_type"`
+	URL        string       `json:"url,omitempty"`
+	IsPositive bool         `json:"srv_send_msg"`        // IsPositive
+	FileData   string       `json:"file_data,omitempty"` // FileData base64 编码的媒体数据, 与 URL 二选一
+	Source     *MediaSource `json:"-"`                   // Source 不为空时发送前转为 URL 或经 Bot.MediaUploader 上传
 }
 
 func (fp *FilePost) String() string {
@@ -47,7 +50,14 @@ func (fp *FilePost) String() string {
 	sb.WriteString("[v2.")
 	sb.WriteString(fp.Type.String())
 	sb.WriteString("]")
-	if fp.URL == "" {
+	if fp.Source != nil {
+		sb.WriteString("来源: ")
+		sb.WriteString(fp.Source.String())
+	} else if fp.FileData != "" {
+		sb.WriteString("数据: ")
+		sb.WriteString(strconv.Itoa(base64.StdEncoding.DecodedLen(len(fp.FileData))))
+		sb.WriteString(" 字节")
+	} else if fp.URL == "" {
 		sb.WriteString("无链接")
 	} else {
 		sb.WriteString("链接: ")
@@ -61,6 +71,10 @@ func (fp *FilePost) String() string {
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html#%E5%8F%91%E9%80%81%E5%88%B0%E5%8D%95%E8%81%8A
 func (bot *Bot) PostFileToQQUser(id string, content *FilePost) (*Message, error) {
 	logrus.Infoln(getLogHeader(), "<= [Q]单:", id+",", content)
+	content, err := bot.resolvefilepost(content)
+	if err != nil {
+		return nil, errors.Wrap(err, getThisFuncName())
+	}
 	return bot.postOpenAPIofMessage("/v2/users/"+id+"/files", "", WriteBodyFromJSON(content))
 }
 
@@ -69,6 +83,10 @@ func (bot *Bot) PostFileToQQUser(id string, content *FilePost) (*Message, error)
 // https://bot.q.qq.com/wiki/develop/api-231017/server-inter/message/send-receive/rich-text-media.html#%E5%8F%91%E9%80%81%E5%88%B0%E7%BE%A4%E8%81%8A
 func (bot *Bot) PostFileToQQGroup(id string, content *FilePost) (*Message, error) {
 	logrus.Infoln(getLogHeader(), "<= [Q]群:", id+",", content)
+	content, err := bot.resolvefilepost(content)
+	if err != nil {
+		return nil, errors.Wrap(err, getThisFuncName())
+	}
 	return bot.postOpenAPIofMessage("/v2/groups/"+id+"/files", "", WriteBodyFromJSON(content))
 }
 
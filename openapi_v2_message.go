@@ -53,3 +53,19 @@ func (bot *Bot) PostMessageToQQGroup(id string, content *MessagePost) (*Message,
 	logrus.Infoln(getLogHeader(), "<= [Q]群:", id+",", content)
 	return bot.postMessageTo("/v2/groups/"+id+"/messages", content)
 }
+
+// DeleteMessageOfQQUser 撤回机器人发送给 openid 指定用户的消息 message_id
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/send-receive/rich-media.html#%E6%92%A4%E5%9B%9E%E6%B6%88%E6%81%AF
+func (bot *Bot) DeleteMessageOfQQUser(id, messageid string) error {
+	logrus.Infoln(getLogHeader(), "<x [Q]单:", id+", 消息:", messageid)
+	return bot.DeleteOpenAPI("/v2/users/"+id+"/messages/"+messageid, "", nil)
+}
+
+// DeleteMessageInQQGroup 撤回机器人在 openid 指定的群发送的消息 message_id
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/server-inter/message/send-receive/rich-media.html#%E6%92%A4%E5%9B%9E%E6%B6%88%E6%81%AF
+func (bot *Bot) DeleteMessageInQQGroup(id, messageid string) error {
+	logrus.Infoln(getLogHeader(), "<x [Q]群:", id+", 消息:", messageid)
+	return bot.DeleteOpenAPI("/v2/groups/"+id+"/messages/"+messageid, "", nil)
+}
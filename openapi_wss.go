@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -46,11 +47,11 @@ func (bot *Bot) GetShardWSSGatewayNoContext() (*ShardWSSGateway, error) {
 	return bot.getOpenAPIofShardWSSGateway("/gateway/bot")
 }
 
-// GetAppAccessTokenNoContext 获取接口凭证并保存到 bot.Token
+// GetAppAccessTokenNoContext 获取接口凭证并保存到 bot.token
 //
 // https://bot.q.qq.com/wiki/develop/api-231017/dev-prepare/interface-framework/api-use.html#%E8%8E%B7%E5%8F%96%E6%8E%A5%E5%8F%A3%E5%87%AD%E8%AF%81
 func (bot *Bot) GetAppAccessTokenNoContext() error {
-	req, err := newHTTPEndpointRequestWithAuth("POST", "", AccessTokenAPI, "", "", WriteBodyFromJSON(&struct {
+	req, err := newHTTPEndpointRequestWithAuth("POST", "", bot.accesstokenurl(), "", "", WriteBodyFromJSON(&struct {
 		A string `json:"appId"`
 		S string `json:"clientSecret"`
 	}{bot.AppID, bot.Secret}))
@@ -84,13 +85,13 @@ func (bot *Bot) GetAppAccessTokenNoContext() error {
 	if body.E == "" {
 		return errors.Wrap(ErrInvalidExpire, getThisFuncName())
 	}
-	bot.token = body.T
-	bot.expiresec, err = strconv.ParseInt(body.E, 10, 64)
+	expiresec, err := strconv.ParseInt(body.E, 10, 64)
 	if err != nil {
 		return errors.Wrap(err, getThisFuncName())
 	}
-	if bot.expiresec <= 0 {
+	if expiresec <= 0 {
 		return errors.Wrap(ErrInvalidExpire, getThisFuncName())
 	}
+	bot.token.Store(&accessToken{token: body.T, expiresec: expiresec, at: time.Now()})
 	return nil
 }
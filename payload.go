@@ -10,6 +10,7 @@ import (
 //
 // https://bot.q.qq.com/wiki/develop/api/gateway/reference.html
 type WebsocketPayload struct {
+	ID string          `json:"id,omitempty"` // ID 仅 HTTP 回调时下发的事件 ID
 	Op OpCode          `json:"op"`
 	D  json.RawMessage `json:"d,omitempty"`
 	S  uint32          `json:"s,omitempty"`
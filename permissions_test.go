@@ -0,0 +1,53 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPermissions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/@me/guilds":
+			_, _ = w.Write([]byte(`[{"id":"1","name":"g"}]`))
+		case "/guilds/1/api_permission":
+			_, _ = w.Write([]byte(`{"apis":[{"path":"/guilds/{guild_id}/members/{user_id}","method":"GET","desc":"获取成员","auth_status":1},` +
+				`{"path":"/guilds/{guild_id}/members/{user_id}","method":"DELETE","desc":"删除成员","auth_status":0}]}`))
+		case "/guilds/1/api_permission/demand":
+			_, _ = w.Write([]byte(`{"guild_id":"1","channel_id":"2","api_identify":{"path":"/guilds/{guild_id}/members/{user_id}","method":"DELETE"},"title":"t","desc":"d"}`))
+		}
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	perms, err := bot.GetAPIPermissionsOfGuild("1")
+	assert.NoError(t, err)
+	assert.Len(t, perms, 2)
+	assert.Equal(t, 1, perms[0].AuthStatus)
+	del := APIPermissionDemandIdentify{Path: "/guilds/{guild_id}/members/{user_id}", Method: http.MethodDelete}
+	demand, err := bot.PostAPIPermissionDemandInGuild("1", "2", del, "d")
+	assert.NoError(t, err)
+	assert.Equal(t, del, demand.APIIdentify)
+
+	hook := test.NewLocal(log.StandardLogger())
+	defer hook.Reset()
+	bot.checkapipermissions(map[APIPermissionDemandIdentify][]string{
+		{Path: del.Path, Method: http.MethodGet}: {"a"},
+		del:                                      {"b"},
+	})
+	var warned []string
+	for _, e := range hook.AllEntries() {
+		if e.Level == log.WarnLevel {
+			warned = append(warned, e.Message)
+		}
+	}
+	assert.Len(t, warned, 1)
+	assert.Contains(t, warned[0], "DELETE")
+	assert.Contains(t, warned[0], "[b]")
+}
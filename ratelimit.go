@@ -0,0 +1,197 @@
+package nano
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRateLimitRetry 429 后最多重试的次数
+	maxRateLimitRetry = 3
+	// defaultRetryAfter 429 未给出 Retry-After 时的等待时间
+	defaultRetryAfter = time.Second
+)
+
+// rateBucket 一个路由与目标的限流状态
+type rateBucket struct {
+	mu    sync.Mutex
+	until time.Time     // until 在此之前的请求需要等待
+	dead  bool          // dead 已从 buckets 中移除
+	sem   chan struct{} // sem 受限期间每次仅放行一个请求, 其响应更新限流状态后才放行下一个
+}
+
+// rateLimiter 按路由模版与目标限流, 每个 bot 一个
+type rateLimiter struct {
+	buckets  sync.Map // buckets map[string]*rateBucket, 仅在受限时创建
+	throttle int64    // throttle 累计等待时间, 单位纳秒
+}
+
+// norelease 未排队时的空 release
+func norelease() {}
+
+// wait 等待 key 解除限流, ctx 结束时返回 ctx.Err()
+//
+// 需要等待时按到达顺序逐个放行, 请求完成并 update 后须调用 release 放行下一个
+func (rl *rateLimiter) wait(ctx context.Context, key string) (waited time.Duration, release func(), err error) {
+	release = norelease
+	v, ok := rl.buckets.Load(key)
+	if !ok {
+		return
+	}
+	b := v.(*rateBucket)
+	b.mu.Lock()
+	d := time.Until(b.until)
+	b.mu.Unlock()
+	if d <= 0 {
+		return
+	}
+	start := time.Now()
+	defer func() {
+		waited = time.Since(start)
+		atomic.AddInt64(&rl.throttle, int64(waited))
+	}()
+	select {
+	case b.sem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, norelease, ctx.Err()
+	}
+	for {
+		b.mu.Lock()
+		d := time.Until(b.until)
+		b.mu.Unlock()
+		if d <= 0 {
+			return 0, func() { <-b.sem }, nil
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			<-b.sem
+			return 0, norelease, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// update 按响应头更新 key 的限流状态, 返回是否为 429
+func (rl *rateLimiter) update(key string, resp *http.Response) bool {
+	var d time.Duration
+	limited := resp.StatusCode == http.StatusTooManyRequests
+	if limited {
+		d = parseRetryAfter(resp.Header.Get("Retry-After"))
+		if d <= 0 {
+			d = defaultRetryAfter
+		}
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		d = parseRetryAfter(resp.Header.Get("X-RateLimit-Reset-After"))
+	}
+	if d <= 0 {
+		return limited
+	}
+	until := time.Now().Add(d)
+	for {
+		v, loaded := rl.buckets.LoadOrStore(key, &rateBucket{sem: make(chan struct{}, 1)})
+		if !loaded {
+			rl.prune(key)
+		}
+		b := v.(*rateBucket)
+		b.mu.Lock()
+		if b.dead {
+			b.mu.Unlock()
+			continue
+		}
+		if until.After(b.until) {
+			b.until = until
+		}
+		b.mu.Unlock()
+		return limited
+	}
+}
+
+// prune 移除 except 以外已解除限流的 bucket
+func (rl *rateLimiter) prune(except string) {
+	now := time.Now()
+	rl.buckets.Range(func(k, v any) bool {
+		if k.(string) == except {
+			return true
+		}
+		b := v.(*rateBucket)
+		b.mu.Lock()
+		if !b.until.After(now) {
+			b.dead = true
+			rl.buckets.CompareAndDelete(k, v)
+		}
+		b.mu.Unlock()
+		return true
+	})
+}
+
+// parseRetryAfter 解析秒数 (可为小数) 或 HTTP 日期
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(sec * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// ratelimitroute 将 ep 转为路由模版并取出首个 ID 作为限流目标
+//
+// ex. /channels/123/messages?x=1 is /channels/{id}/messages, 123
+func ratelimitroute(ep string) (route, target string) {
+	ep, _, _ = strings.Cut(ep, "?")
+	segs := strings.Split(ep, "/")
+	for i, seg := range segs {
+		if !isidsegment(seg) {
+			continue
+		}
+		if target == "" {
+			target = seg
+		}
+		segs[i] = "{id}"
+	}
+	return strings.Join(segs, "/"), target
+}
+
+// isidsegment 含有数字或大写字母的路径段视为 ID, 版本号如 v2 除外
+func isidsegment(seg string) bool {
+	if len(seg) >= 2 && seg[0] == 'v' && strings.Trim(seg[1:], "0123456789") == "" {
+		return false
+	}
+	return strings.ContainsAny(seg, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+// ThrottleTime 因限流累计等待的时间
+func (bot *Bot) ThrottleTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&bot.ratelimiter().throttle))
+}
+
+// ratelimiter 实际使用的限流器, 自动分片时由所有分片共享
+func (bot *Bot) ratelimiter() *rateLimiter {
+	return &bot.tokenholder().limiter
+}
+
+// waitratelimit 等待 method route 对 target 解除限流并记录, 请求完成并更新限流状态后须调用 release
+func (bot *Bot) waitratelimit(ctx context.Context, method, route, target string) (key string, release func(), err error) {
+	key = method + " " + route + "#" + target
+	d, release, err := bot.ratelimiter().wait(ctx, key)
+	if d > 0 {
+		log.Debugln(getLogHeader(), method, route, "目标", target, "限流等待", d)
+		if bot.OnThrottled != nil {
+			bot.OnThrottled(method+" "+route, target, d)
+		}
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package nano
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitRoute(t *testing.T) {
+	route, target := ratelimitroute("/channels/123456/messages?limit=1")
+	assert.Equal(t, "/channels/{id}/messages", route)
+	assert.Equal(t, "123456", target)
+	route, target = ratelimitroute("/v2/groups/E4F2A9C1D3B5/messages")
+	assert.Equal(t, "/v2/groups/{id}/messages", route)
+	assert.Equal(t, "E4F2A9C1D3B5", target)
+	route, target = ratelimitroute("/users/@me")
+	assert.Equal(t, "/users/@me", route)
+	assert.Equal(t, "", target)
+}
+
+func TestRateLimit429(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			w.Header().Set("Retry-After", "0.1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":22009,"message":"msg limit exceed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","content":"ok"}`))
+	}))
+	defer srv.Close()
+	var throttled int32
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		OnThrottled: func(route, target string, d time.Duration) {
+			assert.Equal(t, "POST /channels/{id}/messages", route)
+			assert.Equal(t, "123", target)
+			atomic.AddInt32(&throttled, 1)
+		},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	msg, err := bot.PostMessageToChannel("123", &MessagePost{Content: "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", msg.Content)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&n))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&throttled))
+	assert.Greater(t, bot.ThrottleTime(), 50*time.Millisecond)
+}
+
+func TestRateLimitBuckets(t *testing.T) {
+	var rl rateLimiter
+	count := func() (n int) {
+		rl.buckets.Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		return
+	}
+	ok := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	for i := 0; i < 100; i++ {
+		key := "GET /channels/{id}/messages#" + string(rune('a'+i))
+		_, _, err := rl.wait(context.Background(), key)
+		assert.NoError(t, err)
+		assert.False(t, rl.update(key, ok))
+	}
+	assert.Equal(t, 0, count())
+	limited := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"0.05"}}}
+	assert.True(t, rl.update("a", limited))
+	assert.True(t, rl.update("b", limited))
+	assert.Equal(t, 2, count())
+	d, release, err := rl.wait(context.Background(), "a")
+	release()
+	assert.NoError(t, err)
+	assert.Greater(t, d, time.Duration(0))
+	assert.True(t, rl.update("c", limited))
+	assert.Equal(t, 1, count())
+}
+
+func TestRateLimitOneAtATime(t *testing.T) {
+	var rl rateLimiter
+	limited := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"0.05"}}}
+	assert.True(t, rl.update("a", limited))
+	var mu sync.Mutex
+	var running, peak int
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := rl.wait(context.Background(), "a")
+			assert.NoError(t, err)
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond) // 模拟请求
+			mu.Lock()
+			running--
+			mu.Unlock()
+			release()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, peak) // 解除限流后逐个放行
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, rl.update("a", limited))
+	_, release, err := rl.wait(ctx, "a")
+	assert.ErrorIs(t, err, context.Canceled)
+	release()
+}
@@ -0,0 +1,48 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecall(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"r1"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	triggeredMessages.Delete("recall-trigger")
+	triggeredMessages.Delete("recall-c2c")
+	ctx := &Ctx{Event: Event{Type: "GroupAtMessageCreate"}, IsQQ: true, Message: &Message{ID: "recall-trigger", ChannelID: "G"}, caller: bot}
+	_, err := ctx.SendPlainMessage(false, "a")
+	assert.NoError(t, err)
+	_, err = ctx.SendPlainMessage(false, "b")
+	assert.NoError(t, err)
+	assert.Len(t, GetTriggeredMessages("recall-trigger"), 2)
+	assert.NoError(t, ctx.RecallTriggeredMessages("recall-trigger"))
+	assert.Equal(t, []string{"/v2/groups/G/messages/r1", "/v2/groups/G/messages/r1"}, deleted)
+
+	deleted = nil
+	ctx = &Ctx{Event: Event{Type: "C2cMessageCreate"}, IsQQ: true, Message: &Message{ID: "recall-c2c", Author: &User{ID: "U"}}, caller: bot}
+	logtriggeredmessages("recall-c2c", "r1")
+	assert.NoError(t, ctx.RecallTriggeredMessages("recall-c2c"))
+	ctx = &Ctx{Event: Event{Type: "DirectMessageCreate"}, Message: &Message{ID: "x", GuildID: "D"}, caller: bot}
+	assert.NoError(t, ctx.Recall("m"))
+	assert.Equal(t, []string{"/v2/users/U/messages/r1", "/dms/D/messages/m"}, deleted)
+	assert.ErrorIs(t, (&Ctx{caller: bot}).Recall("m"), ErrNoMessageContext)
+}
@@ -0,0 +1,151 @@
+package nano
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReconnectPolicy 网关断线重连策略
+type ReconnectPolicy interface {
+	// Backoff 第 attempt 次 (从 1 开始) 连续失败后, 下次尝试前的等待时间
+	Backoff(attempt int) time.Duration
+	// MaxResumeAttempts 连续 Resume 失败多少次后放弃恢复并重新 Connect, 0 为不放弃
+	MaxResumeAttempts() int
+}
+
+// ExponentialBackoff 带随机抖动的指数退避
+type ExponentialBackoff struct {
+	Base      time.Duration // Base 首次等待时间
+	Max       time.Duration // Max 最长等待时间
+	Jitter    float64       // Jitter 随机抖动比例, 取值 [0, 1]
+	MaxResume int           // MaxResume 见 ReconnectPolicy.MaxResumeAttempts
+}
+
+// DefaultReconnectPolicy 未设置 Bot.ReconnectPolicy 时使用的策略
+var DefaultReconnectPolicy ReconnectPolicy = &ExponentialBackoff{
+	Base:      time.Second,
+	Max:       time.Minute,
+	Jitter:    0.5,
+	MaxResume: 5,
+}
+
+// Backoff 实现 ReconnectPolicy
+func (eb *ExponentialBackoff) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := eb.Base
+	for i := 1; i < attempt && i < 32 && (eb.Max <= 0 || d < eb.Max); i++ {
+		d *= 2
+	}
+	if eb.Max > 0 && d > eb.Max {
+		d = eb.Max
+	}
+	if eb.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	j := time.Duration(float64(d) * eb.Jitter)
+	return d - j + time.Duration(rand.Float64()*float64(2*j))
+}
+
+// MaxResumeAttempts 实现 ReconnectPolicy
+func (eb *ExponentialBackoff) MaxResumeAttempts() int {
+	return eb.MaxResume
+}
+
+// reconnectPolicy 获得实际使用的策略
+func (bot *Bot) reconnectPolicy() ReconnectPolicy {
+	if bot.ReconnectPolicy != nil {
+		return bot.ReconnectPolicy
+	}
+	return DefaultReconnectPolicy
+}
+
+// identifyWindow 每 MaxConcurrency 次 identify 的时间窗口
+var identifyWindow = 5 * time.Second
+
+// sessionBudget 网关 session_start_limit 的本地计数, 自动分片时由所有分片共享
+type sessionBudget struct {
+	mu        sync.Mutex
+	total     int
+	remaining int
+	resetat   time.Time
+	maxconc   int       // maxconc 每个窗口内可 identify 的次数
+	winstart  time.Time // winstart 当前窗口开始时间
+	wincount  int       // wincount 当前窗口已 identify 的次数
+}
+
+// update 从 /gateway/bot 的返回值更新计数
+func (sb *sessionBudget) update(sgw *ShardWSSGateway) {
+	sb.total = sgw.SessionStartLimit.Total
+	sb.remaining = sgw.SessionStartLimit.Remaining
+	sb.resetat = time.Now().Add(time.Duration(sgw.SessionStartLimit.ResetAfter) * time.Millisecond)
+	sb.maxconc = sgw.SessionStartLimit.MaxConcurrency
+}
+
+// stale 额度是否未知或已过期
+func (sb *sessionBudget) stale() bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.resetat.IsZero() || time.Now().After(sb.resetat)
+}
+
+// waitidentify 消耗一次 identify 额度, 额度耗尽时等待至 ResetAfter,
+// 并保证每 identifyWindow 内至多 MaxConcurrency 次 identify, bot 被关闭则返回 false
+//
+// 应在连接网关前调用, 以免等待期间连接被网关断开; 额度未知或已过期时先请求 /gateway/bot 刷新额度.
+// 等待期间不持有锁, 窗口内的名额在加锁时预留, 醒来后重新检查额度
+func (bot *Bot) waitidentify() bool {
+	sb := bot.budget
+	if sb.stale() {
+		sgw, err := bot.GetShardWSSGatewayNoContext()
+		if err != nil {
+			log.Warnln(getLogHeader(), "获取 session_start_limit 时出现错误:", err)
+		} else {
+			sb.mu.Lock()
+			sb.update(sgw)
+			sb.mu.Unlock()
+		}
+	}
+	for {
+		sb.mu.Lock()
+		if !sb.resetat.IsZero() {
+			if sb.remaining <= 0 {
+				d := time.Until(sb.resetat)
+				if d > 0 {
+					sb.mu.Unlock()
+					log.Warnln(getLogHeader(), "identify 额度已耗尽, 等待", d, "后重试")
+					if !bot.sleep(d) {
+						return false
+					}
+					continue
+				}
+				sb.remaining = sb.total
+				sb.resetat = time.Time{}
+			}
+			sb.remaining--
+		}
+		var d time.Duration
+		if sb.maxconc > 0 {
+			now := time.Now()
+			if now.Sub(sb.winstart) >= identifyWindow {
+				sb.winstart = now
+				sb.wincount = 0
+			} else if sb.wincount >= sb.maxconc { // 预留下一个窗口的名额
+				sb.winstart = sb.winstart.Add(identifyWindow)
+				sb.wincount = 0
+			}
+			sb.wincount++
+			d = sb.winstart.Sub(now)
+		}
+		sb.mu.Unlock()
+		if d > 0 {
+			log.Debugln(getLogHeader(), "identify 并发已达上限", sb.maxconc, ", 等待", d)
+			return bot.sleep(d)
+		}
+		return true
+	}
+}
@@ -0,0 +1,64 @@
+package nano
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	eb := &ExponentialBackoff{Base: time.Second, Max: 10 * time.Second}
+	assert.Equal(t, time.Second, eb.Backoff(1))
+	assert.Equal(t, 2*time.Second, eb.Backoff(2))
+	assert.Equal(t, 8*time.Second, eb.Backoff(4))
+	assert.Equal(t, 10*time.Second, eb.Backoff(5))
+	assert.Equal(t, 10*time.Second, eb.Backoff(1000))
+	eb.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := eb.Backoff(2)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 3*time.Second)
+	}
+}
+
+func TestSessionBudget(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool { return true })
+	defer gw.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: gw.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	assert.True(t, bot.waitidentify()) // 额度未知时先刷新
+	assert.Equal(t, 999, bot.budget.remaining)
+	sgw := &ShardWSSGateway{}
+	sgw.SessionStartLimit.Total = 2
+	sgw.SessionStartLimit.Remaining = 1
+	sgw.SessionStartLimit.ResetAfter = 200
+	bot.budget.update(sgw)
+	start := time.Now()
+	assert.True(t, bot.waitidentify())
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.True(t, bot.waitidentify())
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, 1, bot.budget.remaining)
+}
+
+func TestIdentifyBudgetBeforeDial(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool { return true })
+	defer gw.Close()
+	bot := (&Bot{AppID: "test", Token: "test"}).Init("", gw.url(), [2]byte{0, 1})
+	defer bot.Close()
+	sgw := &ShardWSSGateway{}
+	sgw.SessionStartLimit.Total = 1
+	sgw.SessionStartLimit.ResetAfter = 300
+	bot.budget.update(sgw)
+	done := make(chan struct{})
+	go func() {
+		bot.Connect()
+		close(done)
+	}()
+	time.Sleep(150 * time.Millisecond)
+	assert.Zero(t, atomic.LoadInt32(&gw.conns)) // 等待额度时不占用连接
+	<-done
+	assert.Equal(t, int32(1), atomic.LoadInt32(&gw.conns))
+}
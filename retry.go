@@ -0,0 +1,96 @@
+package nano
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy OpenAPI 请求遇到网络错误或服务端临时错误时的重试策略
+//
+// GET 等幂等方法可自由重试, POST 仅在消息同时带有 msg_id 与 msg_seq 时重试, 此时平台会拒绝重复的消息
+type RetryPolicy struct {
+	MaxAttempts int // MaxAttempts 最多尝试的次数, 不大于 1 则不重试
+	Backoff     interface {
+		Backoff(attempt int) time.Duration
+	} // Backoff 第 attempt 次失败后的等待时间, 可使用 ExponentialBackoff
+	Statuses []int // Statuses 可重试的 HTTP 状态码
+	Codes    []int // Codes 可重试的错误码
+}
+
+// DefaultRetryPolicy 未设置 Bot.RetryPolicy 时使用的策略
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     &ExponentialBackoff{Base: 500 * time.Millisecond, Max: 5 * time.Second, Jitter: 0.2},
+	Statuses: []int{
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+	},
+}
+
+// retrypolicy 获得实际使用的策略
+func (bot *Bot) retrypolicy() *RetryPolicy {
+	if bot.RetryPolicy != nil {
+		return bot.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+// backoff 第 attempt 次失败后的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	return p.Backoff.Backoff(attempt)
+}
+
+// retryable 以 method 发送且失败于 err 的请求能否重试
+func (p *RetryPolicy) retryable(method string, idempotent bool, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		if !idempotent {
+			return false
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrMediaTooLarge) {
+		return false
+	}
+	var apierr *APIError
+	if errors.As(err, &apierr) {
+		for _, s := range p.Statuses {
+			if apierr.Status == s {
+				return true
+			}
+		}
+		for _, c := range p.Codes {
+			if apierr.Code == c {
+				return true
+			}
+		}
+		return false
+	}
+	var uerr *url.Error
+	return errors.As(err, &uerr) && uerr.Op != "parse" // 网络错误
+}
+
+// idempotentBody 标记可安全重试的 POST 请求体
+type idempotentBody struct {
+	io.Reader
+}
+
+// idempotent 将可重读的 body 标记为可安全重试
+func idempotent(body io.Reader) io.Reader {
+	switch b := body.(type) {
+	case *bytes.Buffer:
+		return idempotentBody{bytes.NewReader(b.Bytes())}
+	case io.ReadSeeker, *multipartStream:
+		return idempotentBody{b}
+	}
+	return body
+}
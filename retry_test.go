@@ -0,0 +1,52 @@
+package nano
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var n int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if atomic.AddInt32(&n, 1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","content":"ok","username":"nano"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{
+		AppID: "test", Token: "test", APIBase: srv.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, Backoff: &ExponentialBackoff{Base: time.Millisecond}, Statuses: []int{http.StatusServiceUnavailable}},
+	}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+
+	u, err := bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "nano", u.Username)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&n))
+
+	atomic.StoreInt32(&n, 0)
+	_, err = bot.PostMessageToChannel("123", &MessagePost{Content: "hello"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&n))
+
+	atomic.StoreInt32(&n, 0)
+	bodies = bodies[:0]
+	msg, err := bot.PostMessageToChannel("123", &MessagePost{Content: "hello", ReplyMessageID: "m", Seq: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", msg.Content)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&n))
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.NotEmpty(t, bodies[1])
+}
@@ -0,0 +1,48 @@
+package nano
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRichText(t *testing.T) {
+	var th Thread
+	err := json.Unmarshal([]byte(`{"guild_id":"1","thread_info":{"thread_id":"t","title":"hi",`+
+		`"content":"{\"paragraphs\":[{\"elems\":[{\"text\":{\"text\":\"hello \"},\"type\":1},{\"url\":{\"url\":\"https://a.b\",\"desc\":\"link\"},\"type\":4}],\"props\":{}},`+
+		`{\"elems\":[{\"image\":{\"third_url\":\"https://a.b/c.png\"},\"type\":2},{\"text\":{\"text\":\"world\"},\"type\":1}]}]}"}}`), &th)
+	assert.NoError(t, err)
+	assert.NotNil(t, th.ThreadInfo.Rich)
+	assert.Equal(t, "hello link\nworld", th.ThreadInfo.Rich.PlainText())
+	assert.Equal(t, ElemTypeImage, th.ThreadInfo.Rich.Paragraphs[1].Elems[0].Type)
+
+	var p Post
+	assert.NoError(t, json.Unmarshal([]byte(`{"post_info":{"content":"plain"}}`), &p))
+	assert.Nil(t, p.PostInfo.Rich)
+
+	ctx := &Ctx{Event: Event{Value: &th}, State: State{}}
+	assert.True(t, KeywordRule("world")(ctx))
+	assert.False(t, KeywordRule("paragraphs")(ctx))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			C string `json:"content"`
+			F uint32 `json:"format"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, ThreadFormatJSON, body.F)
+		rt, err := ParseRichText(body.C)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", rt.PlainText())
+		_, _ = w.Write([]byte(`{"task_id":"1","create_time":"2"}`))
+	}))
+	defer srv.Close()
+	bot := (&Bot{AppID: "test", Token: "test", APIBase: srv.URL}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	taskid, _, err := bot.PostRichThreadInChannel("1", "title", &RichText{Paragraphs: []Paragraph{{Elems: []Elem{TextElemOf("a")}}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", taskid)
+}
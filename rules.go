@@ -94,10 +94,10 @@ func CommandGroupRule(commands ...string) Rule {
 		cmdMessage := ""
 		args := ""
 		switch {
-		case strings.HasPrefix(msg.Content, "/"):
+		case strings.HasPrefix(msg.Content, CommandPrefix):
 			cmdMessage, args, _ = strings.Cut(msg.Content, " ")
 			cmdMessage, _, _ = strings.Cut(cmdMessage, "@")
-			cmdMessage = cmdMessage[1:]
+			cmdMessage = cmdMessage[len(CommandPrefix):]
 		default:
 			return false
 		}
@@ -127,6 +127,14 @@ func RegexRule(regexPattern string) Rule {
 			}
 			return false
 		default:
+			text, ok := richtextof(msg)
+			if !ok || text == "" {
+				return false
+			}
+			if matched := regex.FindStringSubmatch(text); matched != nil {
+				ctx.State["regex_matched"] = matched
+				return true
+			}
 			return false
 		}
 	}
@@ -145,6 +153,21 @@ func ReplyRule(messageID string) Rule {
 	}
 }
 
+// ButtonRule check if the interaction is a button whose data has the prefix and trim the prefix
+//
+//	this rule only supports Interaction
+func ButtonRule(prefix string) Rule {
+	return func(ctx *Ctx) bool {
+		it, ok := ctx.Value.(*Interaction)
+		if !ok || !strings.HasPrefix(it.ButtonData(), prefix) {
+			return false
+		}
+		ctx.State["button_data"] = it.ButtonData()
+		ctx.State["args"] = it.ButtonData()[len(prefix):]
+		return true
+	}
+}
+
 func KeywordRule(src string) Rule {
 	return KeywordGroupRule(src)
 }
@@ -165,6 +188,16 @@ func KeywordGroupRule(src ...string) Rule {
 			}
 			return false
 		default:
+			text, ok := richtextof(msg)
+			if !ok || text == "" {
+				return false
+			}
+			for _, str := range src {
+				if strings.Contains(text, str) {
+					ctx.State["keyword"] = str
+					return true
+				}
+			}
 			return false
 		}
 	}
@@ -325,11 +358,17 @@ func OnlyPrivate(ctx *Ctx) bool {
 
 // OnlyQQGroup 只在 QQ 群
 func OnlyQQGroup(ctx *Ctx) bool {
+	if it, ok := ctx.Value.(*Interaction); ok {
+		return it.ChatType == InteractionChatTypeGroup
+	}
 	return ctx.Type == "GroupAtMessageCreate"
 }
 
 // OnlyQQPrivate 只在 QQ 私聊
 func OnlyQQPrivate(ctx *Ctx) bool {
+	if it, ok := ctx.Value.(*Interaction); ok {
+		return it.ChatType == InteractionChatTypeC2C
+	}
 	return ctx.Type == "C2cMessageCreate"
 }
 
@@ -0,0 +1,160 @@
+package nano
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SessionFolder FileSessionStore 的默认目录
+	SessionFolder = "data/session/"
+	// sessionSaveInterval Listen 中保存 seq 的最短间隔
+	sessionSaveInterval = 5 * time.Second
+	// closeCodeResumable 设置了 SessionStore 时 Close 使用的关闭码, 非 1000 的关闭不会结束会话
+	closeCodeResumable = 4000
+)
+
+// GatewaySession 可跨进程恢复的网关会话
+type GatewaySession struct {
+	SessionID string  `json:"session_id"`
+	Seq       uint32  `json:"seq"`
+	Gateway   string  `json:"gateway"`
+	Shard     [2]byte `json:"shard"`
+	User      *User   `json:"user,omitempty"`
+}
+
+// SessionStore 保存网关会话, 使重启后的进程可以 Resume 而非重新 Identify
+type SessionStore interface {
+	// LoadSession 读取 key 对应的会话, 不存在时返回 nil, nil
+	LoadSession(key string) (*GatewaySession, error)
+	// SaveSession 保存 key 对应的会话
+	SaveSession(key string, s *GatewaySession) error
+	// DeleteSession 删除 key 对应的会话
+	DeleteSession(key string) error
+}
+
+// FileSessionStore 将会话以 json 保存于该目录下
+type FileSessionStore string
+
+// DefaultSessionStore 保存于 SessionFolder 的 FileSessionStore
+var DefaultSessionStore SessionStore = FileSessionStore(SessionFolder)
+
+func (fs FileSessionStore) path(key string) string {
+	return filepath.Join(string(fs), key+".json")
+}
+
+// LoadSession 实现 SessionStore
+func (fs FileSessionStore) LoadSession(key string) (*GatewaySession, error) {
+	data, err := os.ReadFile(fs.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &GatewaySession{}
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveSession 实现 SessionStore, 先写入临时文件再重命名以免写坏
+func (fs FileSessionStore) SaveSession(key string, s *GatewaySession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(string(fs), 0755)
+	if err != nil {
+		return err
+	}
+	p := fs.path(key)
+	err = os.WriteFile(p+".tmp", data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(p+".tmp", p)
+}
+
+// DeleteSession 实现 SessionStore
+func (fs FileSessionStore) DeleteSession(key string) error {
+	err := os.Remove(fs.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// sessionkey 在 SessionStore 中的键
+func (bot *Bot) sessionkey() string {
+	return bot.AppID + "_" + strconv.Itoa(int(bot.shard[0]))
+}
+
+// savesession 保存当前会话
+func (bot *Bot) savesession() {
+	if bot.SessionStore == nil || bot.ready.SessionID == "" {
+		return
+	}
+	err := bot.SessionStore.SaveSession(bot.sessionkey(), &GatewaySession{
+		SessionID: bot.ready.SessionID,
+		Seq:       atomic.LoadUint32(&bot.seq),
+		Gateway:   bot.gateway,
+		Shard:     bot.shard,
+		User:      bot.ready.User,
+	})
+	if err != nil {
+		log.Warnln(getLogHeader(), "保存网关会话时出现错误:", err)
+	}
+}
+
+// deletesession 删除已失效的会话, 以免重启后再次 Resume
+func (bot *Bot) deletesession() {
+	if bot.SessionStore == nil {
+		return
+	}
+	if err := bot.SessionStore.DeleteSession(bot.sessionkey()); err != nil {
+		log.Warnln(getLogHeader(), "删除网关会话时出现错误:", err)
+	}
+}
+
+// restoresession 尝试 Resume 已保存的会话, 成功发送 Resume 返回 true
+//
+// 服务端返回 OpCodeInvalidSession 时由 Listen 重新 Connect
+func (bot *Bot) restoresession() bool {
+	s, err := bot.SessionStore.LoadSession(bot.sessionkey())
+	if err != nil {
+		log.Warnln(getLogHeader(), "读取网关会话时出现错误:", err)
+		return false
+	}
+	if s == nil || s.SessionID == "" || s.Shard != bot.shard {
+		return false
+	}
+	if s.User == nil {
+		s.User = &User{}
+	}
+	gateway := bot.gateway
+	if s.Gateway != "" {
+		bot.gateway = s.Gateway
+	}
+	bot.ready = EventReady{SessionID: s.SessionID, User: s.User, Shard: s.Shard}
+	atomic.StoreUint32(&bot.seq, s.Seq)
+	err = bot.Resume()
+	if err != nil {
+		log.Warnln(getLogHeader(), "恢复已保存的网关会话时出现错误:", err)
+		bot.gateway = gateway
+		bot.ready = EventReady{}
+		atomic.StoreUint32(&bot.seq, 0)
+		return false
+	}
+	log.Infoln(getLogHeader(), "尝试恢复已保存的网关会话, 用户名:", s.User.Username, ", seq:", s.Seq)
+	return true
+}
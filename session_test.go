@@ -0,0 +1,84 @@
+package nano
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSessionStore(t *testing.T) {
+	fs := FileSessionStore(t.TempDir())
+	s, err := fs.LoadSession("test_0")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+	assert.NoError(t, fs.SaveSession("test_0", &GatewaySession{SessionID: "fake", Seq: 42, Shard: [2]byte{0, 1}}))
+	s, err = fs.LoadSession("test_0")
+	assert.NoError(t, err)
+	assert.Equal(t, "fake", s.SessionID)
+	assert.Equal(t, uint32(42), s.Seq)
+	assert.NoError(t, fs.DeleteSession("test_0"))
+	assert.NoError(t, fs.DeleteSession("test_0"))
+	s, err = fs.LoadSession("test_0")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestBotRestoreSession(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool { return true })
+	defer gw.Close()
+	fs := FileSessionStore(t.TempDir())
+	assert.NoError(t, fs.SaveSession("test_0", &GatewaySession{
+		SessionID: "saved", Seq: 10, Gateway: gw.url(), Shard: [2]byte{0, 1}, User: &User{Username: "nano"},
+	}))
+	bot := (&Bot{AppID: "test", Token: "test", SessionStore: fs}).Init("", "unused", [2]byte{0, 1}).Connect()
+	go bot.Listen()
+	assert.Equal(t, OpCodeResume, <-gw.ops)
+	assert.Equal(t, "saved", bot.ready.SessionID)
+	assert.NoError(t, bot.Close())
+	assert.Equal(t, closeCodeResumable, <-gw.closes) // 以可恢复的关闭码关闭
+	s, err := fs.LoadSession("test_0")
+	assert.NoError(t, err)
+	assert.Equal(t, "saved", s.SessionID)
+	assert.GreaterOrEqual(t, s.Seq, uint32(10))
+}
+
+// deleteCountingStore 记录 DeleteSession 的调用次数
+type deleteCountingStore struct {
+	FileSessionStore
+	deleted int32
+}
+
+func (s *deleteCountingStore) DeleteSession(key string) error {
+	atomic.AddInt32(&s.deleted, 1)
+	return s.FileSessionStore.DeleteSession(key)
+}
+
+func TestBotRestoreInvalidSession(t *testing.T) {
+	gw := newFakeGateway(func(n, i int) bool { return true })
+	gw.invalid = func(n int) bool { return n == 1 }
+	defer gw.Close()
+	fs := &deleteCountingStore{FileSessionStore: FileSessionStore(t.TempDir())}
+	assert.NoError(t, fs.SaveSession("test_0", &GatewaySession{
+		SessionID: "expired", Seq: 10, Gateway: gw.url(), Shard: [2]byte{0, 1},
+	}))
+	bot := (&Bot{
+		AppID: "test", Token: "test", SessionStore: fs, APIBase: gw.URL,
+		ReconnectPolicy: &ExponentialBackoff{Base: 10 * time.Millisecond},
+	}).Init("", gw.url(), [2]byte{0, 1}).Connect()
+	defer bot.Close()
+	go bot.Listen()
+	assert.Equal(t, OpCodeResume, <-gw.ops)
+	select {
+	case op := <-gw.ops:
+		assert.Equal(t, OpCodeIdentify, op)
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not fall back to identify")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fs.deleted)) // 失效的会话已删除
+	assert.Eventually(t, func() bool {
+		s, err := fs.LoadSession("test_0")
+		return err == nil && s != nil && s.SessionID == "fake"
+	}, 5*time.Second, 10*time.Millisecond)
+}
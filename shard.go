@@ -0,0 +1,161 @@
+package nano
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// https://bot.q.qq.com/wiki/develop/api/gateway/error/error.html
+const (
+	CloseCodeInvalidShard     = 4010 // CloseCodeInvalidShard 无效的 shard
+	CloseCodeShardingRequired = 4011 // CloseCodeShardingRequired 连接需要处理的 guild 过多, 请进行合理的分片
+)
+
+var (
+	ErrTooManyShards = errors.New("too many shards")
+)
+
+// ShardedBot 自动分片, 在同一进程内运行网关推荐数量的全部分片
+//
+// 所有分片共享 Bot 的 Token、HTTP 客户端与 identify 额度, 并按 MaxConcurrency 分批 identify
+type ShardedBot struct {
+	*Bot // Bot 分片共用的配置, 其 ShardIndex 与 ShardCount 将被忽略
+
+	mu      sync.RWMutex
+	shards  []*Bot
+	reshard chan struct{}
+}
+
+// NewShardedBot 以 bot 为配置模版新建自动分片
+func NewShardedBot(bot *Bot) *ShardedBot {
+	return &ShardedBot{
+		Bot:     bot,
+		reshard: make(chan struct{}, 1),
+	}
+}
+
+// Shards 当前运行的全部分片, 下标即分片序号
+func (sb *ShardedBot) Shards() []*Bot {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	shards := make([]*Bot, len(sb.shards))
+	copy(shards, sb.shards)
+	return shards
+}
+
+// ShardInfo 本连接的分片序号与分片总数
+func (bot *Bot) ShardInfo() (index, count int) {
+	return int(bot.shard[0]), int(bot.shard[1])
+}
+
+// Run 获取网关推荐分片数并运行全部分片, 阻塞直到 ctx 结束
+//
+// 网关要求重新分片时, 关闭全部分片并按新的推荐分片数重新运行
+func (sb *ShardedBot) Run(ctx context.Context) error {
+	sb.ctx, sb.cancel = context.WithCancel(ctx)
+	if sb.client == nil {
+		sb.client = sb.newclient()
+	}
+	secret := sb.Secret
+	sb.Secret = "" // 与 getinitinfo 相同, 先使用 Bot Token 获取网关
+	sgw, err := sb.GetShardWSSGatewayNoContext()
+	if err != nil {
+		return err
+	}
+	sb.Init(secret, "", [2]byte{0, 1})
+	for {
+		err = sb.spawn(secret, sgw)
+		if err != nil {
+			return errors.Join(err, sb.Close())
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(closeBots(sb.Shards()...), sb.Close())
+		case <-sb.reshard:
+		}
+		log.Warnln(getLogHeader(), "关闭全部分片并重新分片")
+		_ = closeBots(sb.Shards()...)
+		for {
+			sgw, err = sb.GetShardWSSGatewayNoContext()
+			if err == nil {
+				break
+			}
+			log.Warnln(getLogHeader(), "获取分片网关时出现错误:", err)
+			if !sb.sleep(sb.reconnectPolicy().Backoff(1)) {
+				return sb.Close()
+			}
+		}
+	}
+}
+
+// spawn 按 sgw 新建并启动全部分片
+func (sb *ShardedBot) spawn(secret string, sgw *ShardWSSGateway) error {
+	if sgw.Shards <= 0 {
+		sgw.Shards = 1
+	}
+	if sgw.Shards > 255 {
+		return errors.New(ErrTooManyShards.Error() + ": " + strconv.Itoa(sgw.Shards))
+	}
+	log.Infoln(getLogHeader(), "使用网关推荐Shards数:", sgw.Shards, ", 并发数:", sgw.SessionStartLimit.MaxConcurrency)
+	budget := &sessionBudget{}
+	budget.update(sgw)
+	shards := make([]*Bot, sgw.Shards)
+	for i := range shards {
+		b := sb.cloneconfig()
+		b.ShardIndex = uint8(i)
+		b.ShardCount = uint8(sgw.Shards)
+		b.owner = sb.Bot
+		b.budget = budget
+		b.reshard = sb.signal
+		b.ctx, b.cancel = context.WithCancel(sb.ctx)
+		shards[i] = b.Init(secret, sgw.URL, [2]byte{byte(i), byte(sgw.Shards)})
+	}
+	sb.mu.Lock()
+	sb.shards = shards
+	sb.mu.Unlock()
+	for _, b := range shards {
+		go b.Connect().Listen()
+	}
+	return nil
+}
+
+// signal 通知 Run 重新分片
+func (sb *ShardedBot) signal() {
+	select {
+	case sb.reshard <- struct{}{}:
+	default:
+	}
+}
+
+// cloneconfig 复制 bot 的导出配置
+func (bot *Bot) cloneconfig() *Bot {
+	return &Bot{
+		AppID:                bot.AppID,
+		Token:                bot.Token,
+		SuperUsers:           bot.SuperUsers,
+		Timeout:              bot.Timeout,
+		Handler:              bot.Handler,
+		Intents:              bot.Intents,
+		ShardIndex:           bot.ShardIndex,
+		ShardCount:           bot.ShardCount,
+		Properties:           bot.Properties,
+		ShutdownTimeout:      bot.ShutdownTimeout,
+		HeartbeatMaxMissed:   bot.HeartbeatMaxMissed,
+		TokenRefreshFraction: bot.TokenRefreshFraction,
+		APIBase:              bot.APIBase,
+		AccessTokenURL:       bot.AccessTokenURL,
+		Transport:            bot.Transport,
+		Dialer:               bot.Dialer,
+		ReconnectPolicy:      bot.ReconnectPolicy,
+		SessionStore:         bot.SessionStore,
+		OnThrottled:          bot.OnThrottled,
+		RetryPolicy:          bot.RetryPolicy,
+		Interceptors:         bot.Interceptors,
+		MediaUploader:        bot.MediaUploader,
+		FileInfoCache:        bot.FileInfoCache,
+	}
+}
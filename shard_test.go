@@ -0,0 +1,52 @@
+package nano
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedBotSpawn(t *testing.T) {
+	w := identifyWindow
+	identifyWindow = 300 * time.Millisecond
+	defer func() { identifyWindow = w }()
+	gw := newFakeGateway(func(n, i int) bool { return true })
+	defer gw.Close()
+	sb := NewShardedBot(&Bot{AppID: "test", Token: "test"})
+	sb.ctx, sb.cancel = context.WithCancel(context.Background())
+	sb.Init("", "", [2]byte{0, 1})
+	sgw := &ShardWSSGateway{URL: gw.url(), Shards: 3}
+	sgw.SessionStartLimit.Total = 10
+	sgw.SessionStartLimit.Remaining = 10
+	sgw.SessionStartLimit.ResetAfter = 60000
+	sgw.SessionStartLimit.MaxConcurrency = 2
+	start := time.Now()
+	assert.NoError(t, sb.spawn("", sgw))
+	defer func() {
+		assert.NoError(t, closeBots(sb.Shards()...))
+		assert.NoError(t, sb.Close())
+	}()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, OpCodeIdentify, <-gw.ops)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), identifyWindow) // 第三个分片须等待下一个窗口
+	assert.Eventually(t, func() bool {                          // 全部分片连接成功
+		for _, b := range sb.Shards() {
+			if _, loaded := clients.Load(b.clientkey()); !loaded {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	for i, b := range sb.Shards() {
+		index, count := b.ShardInfo()
+		assert.Equal(t, i, index)
+		assert.Equal(t, 3, count)
+		_, ok := clients.Load(b.clientkey())
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 7, sb.Shards()[0].budget.remaining)
+	assert.Error(t, sb.spawn("", &ShardWSSGateway{Shards: 256}))
+}
@@ -0,0 +1,78 @@
+package nano
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// accessToken 通过 secret 获得的接口凭证
+type accessToken struct {
+	token     string
+	expiresec int64     // expiresec 有效时间
+	at        time.Time // at 获得时间
+}
+
+// tokenholder 实际持有 Token 的 bot, 自动分片时为模版
+func (bot *Bot) tokenholder() *Bot {
+	if bot.owner != nil {
+		return bot.owner
+	}
+	return bot
+}
+
+// accesstoken 当前的接口凭证, 尚未获得时为 nil
+func (bot *Bot) accesstoken() *accessToken {
+	return bot.tokenholder().token.Load()
+}
+
+// tokenrefreshdelay 距 tk 需要刷新的时间
+func (bot *Bot) tokenrefreshdelay(tk *accessToken) time.Duration {
+	if tk == nil {
+		return 0
+	}
+	d := time.Duration(float64(time.Duration(tk.expiresec)*time.Second)*bot.TokenRefreshFraction) - time.Since(tk.at)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// refreshaccesstoken 刷新 stale, 若 Token 已被其它调用刷新则直接返回
+//
+// 并发调用时只有一个会真正请求新 Token
+func (bot *Bot) refreshaccesstoken(stale *accessToken) error {
+	h := bot.tokenholder()
+	h.tkmu.Lock()
+	defer h.tkmu.Unlock()
+	if h.token.Load() != stale {
+		return nil
+	}
+	err := h.GetAppAccessTokenNoContext()
+	if err != nil {
+		return err
+	}
+	tk := h.token.Load()
+	log.Infoln(getLogHeader(), "刷新 Token: "+tk.token+", 超时:", tk.expiresec, "秒")
+	h.lifecycle(EventTypeTokenRefreshed, &LifecycleEvent{})
+	return nil
+}
+
+// refreshtoken 在 Token 有效期的 TokenRefreshFraction 处主动刷新
+//
+// 网关仅在 Identify 与 Resume 时使用 Token, 二者均读取最新的 Token, 无需另行推送
+func (bot *Bot) refreshtoken() {
+	for {
+		tk := bot.token.Load()
+		if !bot.sleep(bot.tokenrefreshdelay(tk)) {
+			return
+		}
+		err := bot.refreshaccesstoken(tk)
+		if err != nil {
+			log.Warnln(getLogHeader(), "刷新 Token 时出现错误:", err)
+			if !bot.sleep(time.Second * 3) {
+				return
+			}
+		}
+	}
+}
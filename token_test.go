@@ -0,0 +1,71 @@
+package nano
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport 将所有请求转发到 host
+type rewriteTransport string
+
+func (host rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, err := url.Parse(string(host))
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTokenRefreshOn401(t *testing.T) {
+	var fetched int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/getAppAccessToken":
+			n := atomic.AddInt32(&fetched, 1)
+			time.Sleep(50 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"access_token":"t` + strconv.Itoa(int(n)+1) + `","expires_in":"7200"}`))
+		case "/users/@me":
+			if r.Header.Get("Authorization") != "QQBot t2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"code":11244,"message":"token not exist or expire"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"1","username":"nano"}`))
+		}
+	}))
+	defer srv.Close()
+	bot := &Bot{AppID: "test", Secret: "test", client: &http.Client{Transport: rewriteTransport(srv.URL)}}
+	bot.token.Store(&accessToken{token: "t1", expiresec: 7200, at: time.Now()})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := bot.GetMyInfo()
+			assert.NoError(t, err)
+			if err == nil {
+				assert.Equal(t, "nano", u.Username)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetched))
+	assert.Equal(t, "QQBot t2", bot.Authorization())
+}
+
+func TestTokenRefreshDelay(t *testing.T) {
+	bot := &Bot{TokenRefreshFraction: 0.5}
+	assert.Equal(t, time.Duration(0), bot.tokenrefreshdelay(nil))
+	d := bot.tokenrefreshdelay(&accessToken{expiresec: 100, at: time.Now()})
+	assert.InDelta(t, float64(50*time.Second), float64(d), float64(time.Second))
+	assert.Equal(t, time.Duration(0), bot.tokenrefreshdelay(&accessToken{expiresec: 100, at: time.Now().Add(-time.Minute)}))
+}
@@ -0,0 +1,115 @@
+package nano
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/RomiChan/websocket"
+)
+
+// apibase 实际使用的 OpenAPI 域名, 未设置 Bot.APIBase 时为 OpenAPI
+func (bot *Bot) apibase() string {
+	if bot.APIBase == "" {
+		return OpenAPI
+	}
+	_, address := resolveURI(bot.APIBase)
+	return address
+}
+
+// accesstokenurl 实际使用的获取接口凭证的 API
+func (bot *Bot) accesstokenurl() string {
+	if bot.AccessTokenURL == "" {
+		return AccessTokenAPI
+	}
+	_, address := resolveURI(bot.AccessTokenURL)
+	return address
+}
+
+// netdialcontext 连接 resolveURI 得到的 network, 支持 unix socket
+func netdialcontext(network string) func(ctx context.Context, _, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		if network == "unix" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			filepath, err := base64.RawURLEncoding.DecodeString(host)
+			if err == nil {
+				addr = BytesToString(filepath)
+			}
+		}
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+}
+
+// hostnetworks APIBase 与 AccessTokenURL 中非 tcp 的 host 及其 network
+func (bot *Bot) hostnetworks() map[string]string {
+	var networks map[string]string
+	for _, u := range []string{bot.APIBase, bot.AccessTokenURL} {
+		if u == "" {
+			continue
+		}
+		network, address := resolveURI(u)
+		if network == "tcp" {
+			continue
+		}
+		x, err := url.Parse(address)
+		if err != nil {
+			continue
+		}
+		if networks == nil {
+			networks = make(map[string]string, 2)
+		}
+		networks[x.Hostname()] = network
+	}
+	return networks
+}
+
+// hostdialcontext 按请求的 host 在 networks 中选择 network, 未列出的 host 使用 tcp
+func hostdialcontext(networks map[string]string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		if n, ok := networks[host]; ok {
+			return netdialcontext(n)(ctx, network, addr)
+		}
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+}
+
+// newclient 新建 OpenAPI 使用的 HTTP 客户端
+//
+// 未设置 Bot.Transport 且 APIBase 或 AccessTokenURL 为 http+unix 等形式时, 按请求的地址使用对应的 network
+func (bot *Bot) newclient() *http.Client {
+	transport := bot.Transport
+	if transport == nil {
+		if networks := bot.hostnetworks(); networks != nil {
+			t := http.DefaultTransport.(*http.Transport).Clone()
+			t.DialContext = hostdialcontext(networks)
+			transport = t
+		}
+	}
+	return &http.Client{
+		Timeout:   bot.Timeout,
+		Transport: transport,
+	}
+}
+
+// wsdialer 连接网关使用的 Dialer, 基于 Bot.Dialer 并支持 unix socket
+func (bot *Bot) wsdialer(network string) *websocket.Dialer {
+	dialer := &websocket.Dialer{}
+	if bot.Dialer != nil {
+		d := *bot.Dialer
+		dialer = &d
+	}
+	if network != "tcp" || (dialer.NetDial == nil && dialer.NetDialContext == nil) {
+		dialer.NetDial = nil
+		dialer.NetDialContext = netdialcontext(network) // support unix socket transport
+	}
+	return dialer
+}
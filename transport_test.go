@@ -0,0 +1,76 @@
+package nano
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func userHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","username":"` + name + `"}`))
+	})
+}
+
+func TestBotAPIBase(t *testing.T) {
+	sandbox, standard := httptest.NewServer(userHandler("sandbox")), httptest.NewServer(userHandler("standard"))
+	defer sandbox.Close()
+	defer standard.Close()
+	a := (&Bot{AppID: "a", Token: "a", APIBase: sandbox.URL}).Init("", "", [2]byte{0, 1})
+	b := (&Bot{AppID: "b", Token: "b", APIBase: standard.URL}).Init("", "", [2]byte{0, 1})
+	defer a.Close()
+	defer b.Close()
+	u, err := a.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "sandbox", u.Username)
+	u, err = b.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "standard", u.Username)
+}
+
+func TestBotAPIBaseUnix(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "api.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip(err)
+	}
+	srv := httptest.NewUnstartedServer(userHandler("unix"))
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+	bot := (&Bot{AppID: "a", Token: "a", APIBase: "http+unix://" + sock + ":"}).Init("", "", [2]byte{0, 1})
+	defer bot.Close()
+	u, err := bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "unix", u.Username)
+}
+
+func TestBotAccessTokenURLUnix(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "token.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip(err)
+	}
+	tokensrv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"unix","expires_in":"7200"}`))
+	}))
+	tokensrv.Listener = l
+	tokensrv.Start()
+	defer tokensrv.Close()
+	apisrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","username":"` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer apisrv.Close()
+	bot := (&Bot{
+		AppID: "a", APIBase: apisrv.URL,
+		AccessTokenURL: "http+unix://" + sock + ":/app/getAppAccessToken",
+	}).Init("a", "", [2]byte{0, 1})
+	defer bot.Close()
+	u, err := bot.GetMyInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "QQBot unix", u.Username)
+}
@@ -0,0 +1,208 @@
+package nano
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookMaxBodySize 回调请求体的最大长度
+	webhookMaxBodySize = 4 * 1024 * 1024
+)
+
+var (
+	ErrEmptySecret      = errors.New("empty secret")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// WebhookValidation OpCodeHTTPCallbackValidation 回调地址验证的请求与响应
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/dev-prepare/interface-framework/event-emit.html#%E5%9B%9E%E8%B0%83%E5%9C%B0%E5%9D%80%E5%8F%8A%E5%9B%9E%E8%B0%83%E9%AA%8C%E8%AF%81
+type WebhookValidation struct {
+	PlainToken string `json:"plain_token"`
+	EventTs    string `json:"event_ts,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+}
+
+// webhookkey 由 Secret 派生 ed25519 密钥
+//
+// Secret 不足 ed25519.SeedSize 时重复填充
+func (bot *Bot) webhookkey() (ed25519.PrivateKey, error) {
+	bot.whonce.Do(func() {
+		if bot.Secret == "" {
+			return
+		}
+		seed := bot.Secret
+		for len(seed) < ed25519.SeedSize {
+			seed = strings.Repeat(seed, 2)
+		}
+		bot.whkey = ed25519.NewKeyFromSeed(StringToBytes(seed[:ed25519.SeedSize]))
+	})
+	if bot.whkey == nil {
+		return nil, ErrEmptySecret
+	}
+	return bot.whkey, nil
+}
+
+// SignWebhook 使用由 Secret 派生的密钥对 timestamp + body 签名, 返回 hex 编码的签名
+func (bot *Bot) SignWebhook(timestamp string, body []byte) (string, error) {
+	key, err := bot.webhookkey()
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 0, len(timestamp)+len(body))
+	msg = append(msg, timestamp...)
+	msg = append(msg, body...)
+	return hex.EncodeToString(ed25519.Sign(key, msg)), nil
+}
+
+// VerifyWebhook 校验回调请求的 X-Signature-Ed25519 与 X-Signature-Timestamp
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/dev-prepare/interface-framework/sign.html
+func (bot *Bot) VerifyWebhook(header http.Header, body []byte) error {
+	key, err := bot.webhookkey()
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize || sig[63]&224 != 0 {
+		return ErrInvalidSignature
+	}
+	timestamp := header.Get("X-Signature-Timestamp")
+	if timestamp == "" {
+		return ErrInvalidSignature
+	}
+	msg := make([]byte, 0, len(timestamp)+len(body))
+	msg = append(msg, timestamp...)
+	msg = append(msg, body...)
+	if !ed25519.Verify(key.Public().(ed25519.PublicKey), msg, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ServeHTTP 处理 HTTP 回调, 使 Bot 实现 http.Handler
+//
+// https://bot.q.qq.com/wiki/develop/api-v2/dev-prepare/interface-framework/event-emit.html
+func (bot *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, webhookMaxBodySize))
+	if err != nil {
+		log.Warnln(getLogHeader(), "读取回调请求时出现错误:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = bot.VerifyWebhook(r.Header, body)
+	if err != nil {
+		log.Warnln(getLogHeader(), "校验回调签名时出现错误:", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	payload := WebsocketPayload{}
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		log.Warnln(getLogHeader(), "解析回调请求时出现错误:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Debug(getLogHeader(), " 接收到回调事件 ", payload.ID, ": ", payload.Op, ", 类型: ", payload.T, ", 数据: ", BytesToString(payload.D))
+	w.Header().Set("Content-Type", "application/json")
+	switch payload.Op {
+	case OpCodeHTTPCallbackValidation:
+		v := WebhookValidation{}
+		err = json.Unmarshal(payload.D, &v)
+		if err != nil {
+			log.Warnln(getLogHeader(), "解析回调地址验证时出现错误:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.Signature, err = bot.SignWebhook(v.EventTs, StringToBytes(v.PlainToken))
+		if err != nil {
+			log.Warnln(getLogHeader(), "签名回调地址验证时出现错误:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		v.EventTs = ""
+		log.Infoln(getLogHeader(), "完成回调地址验证, AppID:", bot.AppID)
+		_ = json.NewEncoder(w).Encode(&v)
+	case OpCodeDispatch:
+		_ = json.NewEncoder(w).Encode(&WebsocketPayload{Op: OpCodeHTTPCallbackACK})
+		bot.processEvent(&payload)
+	default:
+		log.Warn(getLogHeader(), " 忽略未知回调, ID: ", payload.ID, ", Op: ", payload.Op, ", 类型: ", payload.T, ", 数据: ", BytesToString(payload.D))
+		_ = json.NewEncoder(w).Encode(&WebsocketPayload{Op: OpCodeHTTPCallbackACK})
+	}
+}
+
+// InitWebhook 以 HTTP 回调方式初始化, 只需执行一次
+//
+// 回调方式不连接网关, 机器人信息通过 GetMyInfo 获得
+func (bot *Bot) InitWebhook() (*Bot, error) {
+	if bot.Secret == "" {
+		return nil, ErrEmptySecret
+	}
+	if _, err := bot.webhookkey(); err != nil {
+		return nil, err
+	}
+	bot.Init(bot.Secret, "", [2]byte{0, 1})
+	u, err := bot.GetMyInfo()
+	if err != nil {
+		log.Warnln(getLogHeader(), "获取机器人信息时出现错误:", err)
+		u = &User{}
+	}
+	bot.ready.User = u
+	bot.ready.Shard = bot.shard
+	clients.Store(bot.Token+"_"+strconv.Itoa(int(bot.shard[0])), bot)
+	log.Infoln(getLogHeader(), "以回调方式初始化成功, 用户名:", u.Username, ", AppID:", bot.AppID)
+	bot.lifecycle(EventTypeReady, &LifecycleEvent{})
+	bot.checkapipermissionsonce()
+	return bot, nil
+}
+
+// RunWebhook 以 HTTP 回调方式运行 bots 并阻塞于监听 addr
+//
+// 多个 bot 时按请求头 X-Bot-Appid 分发, 如需 TLS 等自定义配置请直接使用 Bot.ServeHTTP
+func RunWebhook(addr string, preblock func(), bots ...*Bot) error {
+	if !atomic.CompareAndSwapUintptr(&isrunning, 0, 1) {
+		log.Warnln(getLogHeader(), "已忽略重复调用的", getThisFuncName())
+	}
+	if len(bots) == 0 {
+		return nil
+	}
+	bm := make(map[string]*Bot, len(bots))
+	for _, b := range bots {
+		_, err := b.InitWebhook()
+		if err != nil {
+			return err
+		}
+		bm[b.AppID] = b
+	}
+	var h http.Handler = bots[0]
+	if len(bots) > 1 {
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, ok := bm[r.Header.Get("X-Bot-Appid")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			b.ServeHTTP(w, r)
+		})
+	}
+	if preblock != nil {
+		preblock()
+	}
+	log.Infoln(getLogHeader(), "开始监听回调地址:", addr)
+	return http.ListenAndServe(addr, h)
+}
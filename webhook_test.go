@@ -0,0 +1,88 @@
+package nano
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedWebhookRequest(t *testing.T, url string, key ed25519.PrivateKey, payload *WebsocketPayload) *http.Request {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := "1700000000"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Signature-Timestamp", ts)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, append([]byte(ts), body...))))
+	return req
+}
+
+func TestWebhook(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize/2)
+	_, err := rand.Read(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := hex.EncodeToString(seed)[:ed25519.SeedSize/2]
+	key := ed25519.NewKeyFromSeed([]byte(strings.Repeat(secret, 2)))
+	bot := &Bot{AppID: "123456", Secret: secret}
+	srv := httptest.NewServer(bot)
+	defer srv.Close()
+
+	t.Run("validation", func(t *testing.T) {
+		payload := &WebsocketPayload{Op: OpCodeHTTPCallbackValidation}
+		_ = payload.WrapData(&WebhookValidation{PlainToken: "Arq0D5A61EgUu4OxUvOp", EventTs: "1725442341"})
+		resp, err := http.DefaultClient.Do(newSignedWebhookRequest(t, srv.URL, key, payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		v := WebhookValidation{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&v))
+		assert.Equal(t, "Arq0D5A61EgUu4OxUvOp", v.PlainToken)
+		sig, err := hex.DecodeString(v.Signature)
+		assert.NoError(t, err)
+		assert.True(t, ed25519.Verify(key.Public().(ed25519.PublicKey), []byte("1725442341Arq0D5A61EgUu4OxUvOp"), sig))
+	})
+
+	t.Run("dispatch", func(t *testing.T) {
+		payload := &WebsocketPayload{ID: "ROBOT1.0_test", Op: OpCodeDispatch, T: "GROUP_ADD_ROBOT"}
+		_ = payload.WrapData(&QQRobotStatus{GroupOpenID: "test"})
+		resp, err := http.DefaultClient.Do(newSignedWebhookRequest(t, srv.URL, key, payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		ack := WebsocketPayload{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&ack))
+		assert.Equal(t, OpCodeHTTPCallbackACK, ack.Op)
+	})
+
+	t.Run("badsign", func(t *testing.T) {
+		_, otherkey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload := &WebsocketPayload{Op: OpCodeDispatch, T: "GROUP_ADD_ROBOT"}
+		resp, err := http.DefaultClient.Do(newSignedWebhookRequest(t, srv.URL, otherkey, payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}